@@ -1,8 +1,10 @@
 package app
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,14 +16,22 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/gen2brain/beeep"
 	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/archive"
+	"github.com/qualidafial/pomo/clock"
 	"github.com/qualidafial/pomo/config"
+	"github.com/qualidafial/pomo/control"
+	"github.com/qualidafial/pomo/history"
 	"github.com/qualidafial/pomo/kanban"
 	"github.com/qualidafial/pomo/message"
 	"github.com/qualidafial/pomo/overlay"
+	"github.com/qualidafial/pomo/planner"
 	"github.com/qualidafial/pomo/prompt"
+	"github.com/qualidafial/pomo/session"
+	"github.com/qualidafial/pomo/stats"
 	"github.com/qualidafial/pomo/store"
 	"github.com/qualidafial/pomo/taskedit"
 	"github.com/qualidafial/pomo/timer"
+	"github.com/qualidafial/pomo/timetravel"
 )
 
 type mode int
@@ -31,47 +41,49 @@ const (
 	modeNewTask
 	modeEditTask
 	modePrompt
-)
-
-type pomoState int
-
-const (
-	// No timer is running
-	pomoIdle pomoState = iota
-	// A pomodoro timer is running
-	pomoActive
-	// A pomodoro timer has finished and the user is reporting what they did
-	pomoEnded
-	// A short break timer is running
-	pomoBreak
-	// A long break timer is running
-	pomoLongBreak
-	// A break timer has finished
-	pomoBreakEnded
+	modeHistory
+	modeArchive
+	modePlan
+	modeStats
+	modeTimeTravel
 )
 
 type Model struct {
 	config config.Config
-	store  *store.Store
+	store  store.Storage
+	clock  clock.Clock
 
 	width  int
 	height int
 
 	mode mode
 
-	pomoState pomoState
-	current   pomo.Pomo
-	previous  []pomo.Pomo
+	session session.Session
+	// actuals holds the number of completed pomodoros each task was worked
+	// on, by task ID.
+	actuals map[string]int
 
 	dirty bool
 	tag   int
 	err   error
 
-	kanban kanban.Model
-	editor taskedit.Model
+	kanban  kanban.Model
+	editor  taskedit.Model
+	history history.Model
+	archive archive.Model
+	planner planner.Model
+	stats   stats.Model
+	travel  timetravel.Model
+
+	// plan is today's plan as loaded at launch or last saved. It is left over
+	// from the day before if the app runs past midnight.
+	plan pomo.Plan
+	// archived holds the IDs of the planned tasks that have been archived.
+	archived map[string]bool
 
 	prompt    prompt.Model
 	onConfirm tea.Msg
+	onInput   func(string) tea.Msg
 
 	timer   timer.Model
 	spinner spinner.Model
@@ -80,20 +92,26 @@ type Model struct {
 	KeyMap KeyMap
 }
 
-func New(cfg config.Config, s *store.Store) Model {
+func New(cfg config.Config, s store.Storage, c clock.Clock) Model {
 	return Model{
 		config: cfg,
 		store:  s,
+		clock:  c,
 
-		width:     0,
-		height:    0,
-		mode:      modeNormal,
-		pomoState: pomoIdle,
+		width:   0,
+		height:  0,
+		mode:    modeNormal,
+		session: session.New(cfg, c),
 
-		kanban:  kanban.New(defaultTasks()),
-		timer:   timer.New(),
+		kanban:  kanban.New(cfg.Workflow, defaultTasks(), c),
+		timer:   timer.New(c),
 		spinner: spinner.New(spinner.WithSpinner(spinner.MiniDot)),
 		editor:  taskedit.New(),
+		history: history.New(c),
+		archive: archive.New(),
+		planner: planner.New(),
+		stats:   stats.New(c),
+		travel:  timetravel.New(),
 		prompt:  prompt.New(),
 		help:    help.New(),
 
@@ -106,6 +124,7 @@ func (m Model) Init() tea.Cmd {
 		tea.EnterAltScreen,
 		tea.DisableMouse,
 		m.loadState(),
+		m.loadPlan(),
 		m.spinner.Tick,
 	)
 }
@@ -117,128 +136,214 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-	case timer.StartMsg, timer.ResetMsg, timer.TickMsg:
+	case timer.StartMsg, timer.PauseMsg, timer.ResetMsg, timer.TickMsg:
 		m.timer, cmd = m.timer.Update(msg)
 	case timer.TimeoutMsg:
 		err := beeep.Beep(0, 0)
 		if err != nil {
 			log.Error("sending beep on timer expiration", "err", err)
 		}
-		switch m.pomoState {
-		case pomoActive:
-			m.pomoState = pomoEnded
-			cmd = m.timer.Reset()
-			err := beeep.Notify("pomo", "Pomodoro completed! Update your task statuses and start your break!", "")
-			if err != nil {
-				log.Error("sending notification at end of pomodoro", "err", err)
+		if m.session.Allowed(session.Timeout{}) != nil {
+			break
+		}
+		ended := m.session.State()
+		cmd = m.transition(session.Timeout{})
+		var notification string
+		switch ended {
+		case session.Active:
+			notification = "Pomodoro completed! Update your task statuses and start your break!"
+			if m.session.State() != session.Ended {
+				notification = "Pomodoro completed! Time for a break!"
+			}
+		default:
+			notification = "Break's over! Time to start another pomodoro!"
+			if m.session.State() == session.Active {
+				notification = "Break's over! Next pomodoro started."
 			}
-		case pomoBreak, pomoLongBreak:
-			m.pomoState = pomoBreakEnded
-			beeep.Notify("pomo", "Break's over! Time to start another pomodoro!", "")
-			log.Error("sending notification at end of break", "err", err)
+		}
+		err = beeep.Notify("pomo", notification, "")
+		if err != nil {
+			log.Error("sending notification at end of timer", "err", err)
 		}
 	case spinner.TickMsg:
 		m.spinner, cmd = m.spinner.Update(msg)
 	case message.NewTaskMsg:
-		cmd = m.InputNewTask(msg.Status)
+		if msg.Name != "" {
+			cmd = m.kanban.AppendSelect(pomo.Task{
+				ID:        pomo.NewTaskID(),
+				Status:    msg.Status,
+				Name:      msg.Name,
+				UpdatedAt: m.clock.Now(),
+			})
+		} else {
+			cmd = m.InputNewTask(msg.Status)
+		}
 	case message.EditTaskMsg:
 		cmd = m.EditTask(msg.Task)
 	case message.PromptDeleteTaskMsg:
 		m.SetPrompt(fmt.Sprintf("Delete task %q?", msg.Task.Name), DeleteTaskMsg{})
 	case message.TasksModifiedMsg:
-		m.current.Tasks = m.kanban.Tasks()
+		m.session.SetTasks(m.kanban.Tasks())
 		m.dirty = true
 		m.tag++
-		cmd = tea.Tick(250*time.Millisecond, func(_ time.Time) tea.Msg {
+		tag := m.tag
+		cmd = func() tea.Msg {
+			<-m.clock.After(250 * time.Millisecond)
 			return debounceSaveMsg{
-				tag: m.tag,
+				tag: tag,
 			}
-		})
+		}
 		cmd = tea.Batch(cmd, m.spinner.Tick)
 	case debounceSaveMsg:
 		if msg.tag == m.tag {
 			cmd = m.saveState()
 		}
+	case message.LoadHistoryMsg:
+		pomos, err := m.store.List(msg.Day, msg.Day.AddDate(0, 0, 1))
+		if err != nil {
+			cmd = message.Err(fmt.Errorf("loading history: %w", err))
+			break
+		}
+		m.mode = modeHistory
+		cmd = m.history.SetPomos(msg.Day, pomos)
+	case message.CloseHistoryMsg:
+		m.mode = modeNormal
+	case message.LoadArchiveMsg:
+		tasks, err := m.store.ListArchive()
+		if err != nil {
+			cmd = message.Err(fmt.Errorf("loading archive: %w", err))
+			break
+		}
+		m.mode = modeArchive
+		cmd = m.archive.SetTasks(tasks)
+	case message.CloseArchiveMsg:
+		m.mode = modeNormal
+	case message.LoadStatsMsg:
+		pomos, err := m.store.List()
+		if err != nil {
+			cmd = message.Err(fmt.Errorf("loading stats: %w", err))
+			break
+		}
+		m.mode = modeStats
+		m.stats.SetPomos(pomos, m.config.DailyGoal)
+	case message.CloseStatsMsg:
+		m.mode = modeNormal
+	case message.LoadSnapshotsMsg:
+		times, err := m.store.Snapshots()
+		if err != nil {
+			cmd = message.Err(fmt.Errorf("loading snapshots: %w", err))
+			break
+		}
+		m.mode = modeTimeTravel
+		cmd = m.travel.SetSnapshots(times, m.kanban.Tasks())
+	case message.LoadSnapshotMsg:
+		snapshot, err := m.store.GetSnapshot(msg.Time)
+		if err != nil {
+			cmd = message.Err(err)
+			break
+		}
+		m.travel.SetSnapshot(msg.Time, snapshot)
+	case message.RestoreSnapshotMsg:
+		m.mode = modeNormal
+		tasks := slices.Clone(msg.Snapshot.Tasks)
+		assignTaskIDs(tasks)
+		cmd = m.kanban.ReplaceTasks(tasks)
+	case message.CloseTimeTravelMsg:
+		m.mode = modeNormal
+	case message.LoadPlanMsg:
+		m.plan = msg.Plan
+		m.archived = make(map[string]bool)
+		for _, id := range msg.Archived {
+			m.archived[id] = true
+		}
+		if msg.Open {
+			m.openPlan()
+		}
+	case message.OpenPlanMsg:
+		m.openPlan()
+	case message.SavePlanMsg:
+		err := m.store.SavePlan(msg.Plan)
+		if err != nil {
+			cmd = message.Err(err)
+			break
+		}
+		m.plan = msg.Plan
+		m.mode = modeNormal
+		cmd = m.kanban.UpdateTasks(msg.Tasks)
+	case message.ClosePlanMsg:
+		m.mode = modeNormal
+	case message.RestoreTaskMsg:
+		task := msg.Task.Task
+		task.Status = m.config.Workflow.First()
+		task.UpdatedAt = m.clock.Now()
+
+		// save the task to the board before taking it out of the archive, so
+		// it can't be lost in between
+		current := m.session.Current()
+		current.Tasks = append(m.kanban.Tasks(), task)
+		err := m.store.SaveCurrent(current)
+		if err != nil {
+			cmd = message.Err(fmt.Errorf("restoring task: %w", err))
+			break
+		}
+		cmd = tea.Batch(m.kanban.AppendSelect(task), message.LoadArchive)
+		err = m.store.Unarchive(task.ID)
+		if err != nil {
+			cmd = tea.Batch(cmd, message.Err(err))
+			break
+		}
+		delete(m.archived, task.ID)
 	case message.ErrMsg:
 		m.err = msg.Err
 		log.Errorf("%v", msg.Err)
-		cmd = tea.Tick(2*time.Second, func(_ time.Time) tea.Msg {
+		cmd = func() tea.Msg {
+			<-m.clock.After(2 * time.Second)
 			return clearErrMsg{}
-		})
+		}
 
 	case message.LoadStateMsg:
-		m.current = msg.Current
-		m.previous = msg.Previous
-
-		now := time.Now()
-		year, month, day := now.Date()
-		today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
-
-		cmd = m.kanban.SetTasks(m.current.Tasks)
-
-		// infer current pomodoro state from start/end dates:
-		// state        start          end
-		// ==================================
-		// idle         zero           n/a
-		// break        future         n/a
-		// active       past           future
-		// ended        past           past
-		// idle         before today   zero (a break that ended yesterday)
-		// break ended  earlier today  zero (a break whose resume time has passed)
-		switch {
-		case m.current.Start.IsZero():
-			// no start date: idle
-			m.pomoState = pomoIdle
-		case m.current.Start.Compare(now) >= 0:
-			// start > now: on a break
-			m.pomoState = pomoBreak
-
-			// infer break vs long break by the number of completed pomos today
-			if len(m.previous) > 0 && len(m.previous)%4 == 0 {
-				m.pomoState = pomoLongBreak
-			}
-
-			cmd = tea.Batch(cmd, m.timer.Start(m.current.Start))
-		// start < now guaranteed from here on
-		case m.current.End.After(now):
-			// start < now < end: in an active pomodoro
-			m.pomoState = pomoActive
-			cmd = tea.Batch(cmd, m.timer.Start(m.current.End))
-		case !m.current.End.IsZero():
-			// start < end < now: pomodoro has ended
-			m.pomoState = pomoEnded
-		// end is guaranteed empty from here on
-		case m.current.Start.After(today):
-			// today < start < now: break whose resume time was earlier today
-			m.pomoState = pomoBreakEnded
-		default:
-			// today < start: break whose resume time was before today
-			m.pomoState = pomoIdle
-			m.current.Start = time.Time{}
-			m.current.End = time.Time{}
+		var actualsCmd tea.Cmd
+		if msg.Actuals != nil {
+			m.actuals = msg.Actuals
+			actualsCmd = m.kanban.SetActuals(m.actuals)
+		}
+		result := m.session.Load(msg.Current, msg.Previous)
+		var errCmd tea.Cmd
+		next := m.session
+		started := next.AutoStart()
+		if err := m.saveFinished(started); err != nil {
+			// stay in the loaded state, so the pomodoro can be completed later
+			errCmd = message.Err(err)
+		} else {
+			m.session = next
+			started.Changed = started.Changed || result.Changed
+			result = started
+		}
+		cmd = tea.Batch(actualsCmd, m.kanban.SetTasks(m.session.Current().Tasks), m.apply(result), errCmd)
+		if m.mode == modePlan {
+			m.planner.SetTasks(m.plannable())
 		}
-
 		m.dirty = false
 	case DeleteTaskMsg:
 		cmd = m.kanban.Remove()
+	case control.RequestMsg:
+		m, cmd = m.updateControl(msg)
+	case StartPomoMsg:
+		cmd = m.transition(session.Start{})
 	case CancelPomoMsg:
-		if m.pomoState == pomoActive {
-			m.pomoState = pomoIdle
-			m.current.Start = time.Time{}
-			m.current.End = time.Time{}
-			cmd = tea.Batch(m.timer.Reset(), m.saveState())
-		}
+		cmd = m.transition(session.Cancel{
+			Outcome: msg.Outcome,
+			Reason:  msg.Reason,
+		})
+	case LogInterruptionMsg:
+		cmd = m.transition(session.Interrupt{
+			Kind: msg.Kind,
+			Note: msg.Note,
+		})
 	case CompletePomoMsg:
-		if m.pomoState == pomoEnded {
-			cmd = m.completePomo()
-		}
+		cmd = m.transition(session.Complete{})
 	case CancelBreakMsg:
-		if m.pomoState == pomoBreak || m.pomoState == pomoLongBreak {
-			m.pomoState = pomoIdle
-			m.current.Start = time.Time{}
-			m.current.End = time.Time{}
-			cmd = m.saveState()
-		}
+		cmd = m.transition(session.CancelBreak{})
 	default:
 		switch m.mode {
 		case modeNormal:
@@ -247,15 +352,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m, cmd = m.updateEditing(msg)
 		case modePrompt:
 			m, cmd = m.updatePrompt(msg)
+		case modeHistory:
+			m, cmd = m.updateHistory(msg)
+		case modeArchive:
+			m, cmd = m.updateArchive(msg)
+		case modePlan:
+			m, cmd = m.updatePlan(msg)
+		case modeStats:
+			m, cmd = m.updateStats(msg)
+		case modeTimeTravel:
+			m, cmd = m.updateTimeTravel(msg)
 		}
 	}
 
 	_, selection := m.kanban.Task()
 
-	m.KeyMap.StartPomo.SetEnabled(m.pomoState == pomoIdle || m.pomoState == pomoBreakEnded)
-	m.KeyMap.CancelPomo.SetEnabled(m.pomoState == pomoActive)
-	m.KeyMap.StartBreak.SetEnabled(m.pomoState == pomoEnded)
-	m.KeyMap.CancelBreak.SetEnabled(m.pomoState == pomoBreak || m.pomoState == pomoLongBreak)
+	allowed := func(e session.Event) bool {
+		return m.session.Allowed(e) == nil
+	}
+	m.KeyMap.StartPomo.SetEnabled(allowed(session.Start{}))
+	m.KeyMap.CancelPomo.SetEnabled(allowed(session.Cancel{}))
+	m.KeyMap.InterruptPomo.SetEnabled(allowed(session.Cancel{}))
+	m.KeyMap.LogInternal.SetEnabled(allowed(session.Interrupt{}))
+	m.KeyMap.LogExternal.SetEnabled(allowed(session.Interrupt{}))
+	m.KeyMap.StartBreak.SetEnabled(allowed(session.Complete{}))
+	m.KeyMap.CancelBreak.SetEnabled(allowed(session.CancelBreak{}))
+	m.KeyMap.Pause.SetEnabled(allowed(session.Pause{}))
+	m.KeyMap.Resume.SetEnabled(allowed(session.Resume{}))
 
 	m.KeyMap.EditTask.SetEnabled(selection)
 	m.KeyMap.DeleteTask.SetEnabled(selection)
@@ -266,7 +389,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) SetPrompt(prompt string, onConfirm tea.Msg) {
 	m.mode = modePrompt
 	m.prompt.Prompt = prompt
+	m.prompt.ClearInput()
 	m.onConfirm = onConfirm
+	m.onInput = nil
+}
+
+// SetInputPrompt prompts the user for confirmation along with a line of text,
+// which is passed to onConfirm to build the message sent on confirmation.
+func (m *Model) SetInputPrompt(prompt, placeholder string, onConfirm func(string) tea.Msg) tea.Cmd {
+	m.mode = modePrompt
+	m.prompt.Prompt = prompt
+	m.onConfirm = nil
+	m.onInput = onConfirm
+	return m.prompt.SetInput(placeholder)
 }
 
 func (m Model) updateNormal(msg tea.Msg) (Model, tea.Cmd) {
@@ -275,6 +410,8 @@ func (m Model) updateNormal(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
+		case m.kanban.Filtering():
+			m.kanban, cmd = m.kanban.Update(msg)
 		case key.Matches(msg, m.KeyMap.ToggleHelp):
 			m.ToggleHelp()
 		case key.Matches(msg, m.KeyMap.NewTask):
@@ -290,16 +427,45 @@ func (m Model) updateNormal(msg tea.Msg) (Model, tea.Cmd) {
 				cmd = message.PromptDeleteTask(task)
 			}
 		case key.Matches(msg, m.KeyMap.StartPomo):
-			m.pomoState = pomoActive
-			m.current.Start = time.Now()
-			m.current.End = m.current.Start.Add(m.config.PomodoroDuration)
-			cmd = tea.Batch(m.timer.Start(m.current.End), m.saveState())
+			cmd = func() tea.Msg {
+				return StartPomoMsg{}
+			}
 		case key.Matches(msg, m.KeyMap.CancelPomo):
-			m.SetPrompt("Cancel pomodoro?", CancelPomoMsg{})
+			cmd = m.SetInputPrompt("Cancel pomodoro?", "reason (optional)", func(reason string) tea.Msg {
+				return CancelPomoMsg{
+					Outcome: pomo.Cancelled,
+					Reason:  reason,
+				}
+			})
+		case key.Matches(msg, m.KeyMap.InterruptPomo):
+			cmd = m.SetInputPrompt("Pomodoro interrupted?", "reason (optional)", func(reason string) tea.Msg {
+				return CancelPomoMsg{
+					Outcome: pomo.Interrupted,
+					Reason:  reason,
+				}
+			})
+		case key.Matches(msg, m.KeyMap.LogInternal):
+			cmd = m.promptInterruption(pomo.Internal)
+		case key.Matches(msg, m.KeyMap.LogExternal):
+			cmd = m.promptInterruption(pomo.External)
 		case key.Matches(msg, m.KeyMap.StartBreak):
 			m.SetPrompt("Complete pomodoro and start break?", CompletePomoMsg{})
 		case key.Matches(msg, m.KeyMap.CancelBreak):
 			m.SetPrompt("Cancel break early?", CancelBreakMsg{})
+		case key.Matches(msg, m.KeyMap.Pause):
+			cmd = m.transition(session.Pause{})
+		case key.Matches(msg, m.KeyMap.Resume):
+			cmd = m.transition(session.Resume{})
+		case key.Matches(msg, m.KeyMap.History):
+			cmd = m.history.Open(m.clock.Now())
+		case key.Matches(msg, m.KeyMap.Archive):
+			cmd = m.archive.Open()
+		case key.Matches(msg, m.KeyMap.Plan):
+			cmd = m.planner.Open()
+		case key.Matches(msg, m.KeyMap.Stats):
+			cmd = m.stats.Open()
+		case key.Matches(msg, m.KeyMap.TimeTravel):
+			cmd = m.travel.Open()
 		case key.Matches(msg, m.KeyMap.Quit):
 			return m, tea.Quit
 		default:
@@ -311,13 +477,265 @@ func (m Model) updateNormal(msg tea.Msg) (Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m Model) updateHistory(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.ToggleHelp):
+			m.ToggleHelp()
+		case key.Matches(msg, m.KeyMap.Quit):
+			return m, tea.Quit
+		default:
+			m.history, cmd = m.history.Update(msg)
+		}
+	default:
+		m.history, cmd = m.history.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) updateArchive(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case m.archive.Filtering():
+			m.archive, cmd = m.archive.Update(msg)
+		case key.Matches(msg, m.KeyMap.ToggleHelp):
+			m.ToggleHelp()
+		case key.Matches(msg, m.KeyMap.Quit):
+			return m, tea.Quit
+		default:
+			m.archive, cmd = m.archive.Update(msg)
+		}
+	default:
+		m.archive, cmd = m.archive.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) updatePlan(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.ToggleHelp):
+			m.ToggleHelp()
+		case key.Matches(msg, m.KeyMap.Quit):
+			return m, tea.Quit
+		default:
+			m.planner, cmd = m.planner.Update(msg)
+		}
+	default:
+		m.planner, cmd = m.planner.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) updateStats(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.ToggleHelp):
+			m.ToggleHelp()
+		case key.Matches(msg, m.KeyMap.Quit):
+			return m, tea.Quit
+		default:
+			m.stats, cmd = m.stats.Update(msg)
+		}
+	default:
+		m.stats, cmd = m.stats.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) updateTimeTravel(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.ToggleHelp):
+			m.ToggleHelp()
+		case key.Matches(msg, m.KeyMap.Quit):
+			return m, tea.Quit
+		default:
+			m.travel, cmd = m.travel.Update(msg)
+		}
+	default:
+		m.travel, cmd = m.travel.Update(msg)
+	}
+	return m, cmd
+}
+
+// openPlan opens the planning screen for today.
+func (m *Model) openPlan() {
+	m.mode = modePlan
+	m.planner.SetPlan(m.today(), m.config.DailyGoal, m.plannable(), m.plan)
+}
+
+// plannable returns the tasks on the board that are not done.
+func (m Model) plannable() []pomo.Task {
+	var tasks []pomo.Task
+	for _, task := range m.kanban.Tasks() {
+		if !m.config.Workflow.Done(task.Status) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// todaysPlan returns the plan for today, if any tasks were planned.
+func (m Model) todaysPlan() (pomo.Plan, bool) {
+	if !m.plan.Day.Equal(m.today()) || len(m.plan.Tasks) == 0 {
+		return pomo.Plan{}, false
+	}
+	return m.plan, true
+}
+
+// finishedTasks returns the number of tasks in the plan that are done, i.e. in
+// the done column or archived.
+func (m Model) finishedTasks(plan pomo.Plan) int {
+	done := make(map[string]bool)
+	for _, task := range m.kanban.Tasks() {
+		if m.config.Workflow.Done(task.Status) {
+			done[task.ID] = true
+		}
+	}
+	var finished int
+	for _, task := range plan.Tasks {
+		if done[task.ID] || m.archived[task.ID] {
+			finished++
+		}
+	}
+	return finished
+}
+
+// markArchived records the tasks as archived, for plan progress.
+func (m *Model) markArchived(tasks []pomo.Task) {
+	archived := make(map[string]bool, len(m.archived)+len(tasks))
+	maps.Copy(archived, m.archived)
+	for _, task := range tasks {
+		archived[task.ID] = true
+	}
+	m.archived = archived
+}
+
+func (m Model) today() time.Time {
+	now := m.clock.Now()
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
+
+// updateControl applies a request received on the control socket by
+// translating it into the equivalent app message, and replies with the
+// resulting status. Requests the client has stopped waiting for are ignored,
+// so they aren't applied after being reported as timed out.
+func (m Model) updateControl(msg control.RequestMsg) (Model, tea.Cmd) {
+	if !msg.Accept() {
+		return m, nil
+	}
+
+	var translated tea.Msg
+	var err error
+
+	req := msg.Request
+	switch req.Verb {
+	case control.VerbStatus:
+	case control.VerbStart:
+		err = m.session.Allowed(session.Start{})
+		translated = StartPomoMsg{}
+	case control.VerbCancel:
+		switch m.session.State() {
+		case session.Active:
+			translated = CancelPomoMsg{
+				Outcome: pomo.Cancelled,
+				Reason:  req.Reason,
+			}
+		case session.Break, session.LongBreak:
+			translated = CancelBreakMsg{}
+		default:
+			err = fmt.Errorf("no pomodoro or break to cancel while %s", m.session.Describe())
+		}
+	case control.VerbComplete:
+		err = m.session.Allowed(session.Complete{})
+		translated = CompletePomoMsg{}
+	case control.VerbAddTask:
+		status := m.config.Workflow.First()
+		if req.Status != "" {
+			status, err = m.config.Workflow.ParseStatus(req.Status)
+		}
+		if err == nil && req.Name == "" {
+			err = errors.New("task name is required")
+		}
+		translated = message.NewTaskMsg{
+			Status: status,
+			Name:   req.Name,
+		}
+	default:
+		err = fmt.Errorf("unknown verb: %s", req.Verb)
+	}
+
+	if err != nil {
+		msg.Reply(control.Response{
+			Error: err.Error(),
+		})
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if translated != nil {
+		var next tea.Model
+		next, cmd = m.Update(translated)
+		m = next.(Model)
+	}
+
+	status := m.controlStatus()
+	msg.Reply(control.Response{
+		OK:     true,
+		Status: &status,
+	})
+	return m, cmd
+}
+
+func (m Model) controlStatus() control.Status {
+	completed := len(m.session.Completed())
+	status := control.Status{
+		State:     m.session.State().String(),
+		Paused:    m.session.Paused(),
+		Pomo:      completed + 1,
+		Completed: completed,
+		DailyGoal: m.config.DailyGoal,
+		Remaining: int(m.session.Remaining().Round(time.Second) / time.Second),
+	}
+	if end, ok := m.session.End(); ok {
+		status.End = &end
+	}
+	return status
+}
+
+func (m *Model) promptInterruption(kind pomo.InterruptionKind) tea.Cmd {
+	return m.SetInputPrompt(fmt.Sprintf("Log %s interruption?", kind), "note (optional)", func(note string) tea.Msg {
+		return LogInterruptionMsg{
+			Kind: kind,
+			Note: note,
+		}
+	})
+}
+
 func (m Model) updateEditing(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 	case message.SaveTaskMsg:
 		task := m.editor.Task()
-		task.UpdatedAt = time.Now()
+		task.UpdatedAt = m.clock.Now()
 		if m.mode == modeNewTask {
 			cmd = m.kanban.AppendSelect(task)
 		} else {
@@ -339,8 +757,12 @@ func (m Model) updatePrompt(msg tea.Msg) (Model, tea.Cmd) {
 	case prompt.ConfirmMsg:
 		if msg.ID == m.prompt.ID() {
 			m.mode = modeNormal
+			onConfirm := m.onConfirm
+			if m.onInput != nil {
+				onConfirm = m.onInput(msg.Value)
+			}
 			cmd = func() tea.Msg {
-				return m.onConfirm
+				return onConfirm
 			}
 		}
 	case prompt.CancelMsg:
@@ -367,11 +789,25 @@ func (m Model) View() string {
 		sections = append(sections, callToAction)
 	}
 
+	body := m.kanban.View()
+	switch m.mode {
+	case modeHistory:
+		body = m.history.View()
+	case modeArchive:
+		body = m.archive.View()
+	case modePlan:
+		body = m.planner.View()
+	case modeStats:
+		body = m.stats.View()
+	case modeTimeTravel:
+		body = m.travel.View()
+	}
+
 	sections = append(sections,
-		m.kanban.View(),
+		body,
 		m.viewFooter(),
 	)
-	if m.mode == modeNormal && m.help.ShowAll {
+	if m.mode != modeNewTask && m.mode != modeEditTask && m.mode != modePrompt && m.help.ShowAll {
 		sections = append(sections, Help.Render(m.help.View(m)))
 	}
 
@@ -394,13 +830,17 @@ func (m Model) View() string {
 }
 
 func (m Model) viewCallToAction() string {
+	if m.mode != modeNormal && m.mode != modeNewTask && m.mode != modeEditTask && m.mode != modePrompt {
+		return ""
+	}
+
 	var callToAction string
-	switch m.pomoState {
-	case pomoIdle:
+	switch m.session.State() {
+	case session.Idle:
 		callToAction = "No pomodoro active."
-	case pomoEnded:
+	case session.Ended:
 		callToAction = "Your pomodoro has ended. Update tasks and start your break!"
-	case pomoBreakEnded:
+	case session.BreakEnded:
 		callToAction = "Your break is over. Time to start another pomodoro!"
 	default:
 		return ""
@@ -411,42 +851,41 @@ func (m Model) viewCallToAction() string {
 }
 
 func (m Model) viewFooter() string {
-	var state string
-
-	switch m.pomoState {
-	case pomoIdle:
-		state = "idle"
-	case pomoEnded:
-		state = fmt.Sprintf("pomo %d ended -- report tasks", len(m.previous)+1)
-	case pomoBreakEnded:
-		state = "break ended -- start another pomo"
-	case pomoActive:
-		state = fmt.Sprintf("pomo %d in progress", len(m.previous)+1)
-	case pomoBreak:
-		state = "on a break"
-	case pomoLongBreak:
-		state = "on a long break"
-	}
-	state = FooterState.Render(state)
+	state := FooterState.Render(m.session.Describe())
 
 	timer := FooterTimer.Render("🍅", m.timer.View(), "🍅")
 
+	var interruptions string
+	if current := m.session.Current(); m.session.State() == session.Active && len(current.Interruptions) > 0 {
+		interruptions = FooterInterruptions.Render(fmt.Sprintf("%s%d %s%d",
+			pomo.Internal.Mark(), current.CountInterruptions(pomo.Internal),
+			pomo.External.Mark(), current.CountInterruptions(pomo.External)))
+	}
+
+	completed := len(m.session.Completed())
+
+	// with a plan for the day, progress is measured against the plan rather
+	// than the daily goal
+	goal := m.config.DailyGoal
+	plan, planned := m.todaysPlan()
+	if planned && plan.Estimate() > 0 {
+		goal = plan.Estimate()
+	}
+
 	var pomosToday strings.Builder
-	if m.config.DailyGoal > 0 && len(m.previous) >= m.config.DailyGoal {
+	if goal > 0 && completed >= goal {
 		pomosToday.WriteString("🏆 ")
 	}
-	pomosToday.WriteString(strconv.Itoa(len(m.previous)))
-	if m.config.DailyGoal > 0 {
-		pomosToday.WriteRune('/')
-		pomosToday.WriteString(strconv.Itoa(m.config.DailyGoal))
-	}
-	if len(m.previous) == 1 && m.config.DailyGoal == 0 {
-		pomosToday.WriteString(" pomo")
+	if goal > 0 {
+		fmt.Fprintf(&pomosToday, "%d/%d pomos", completed, goal)
 	} else {
-		pomosToday.WriteString(" pomos")
+		pomosToday.WriteString(pomo.Plural(completed, "pomo"))
+	}
+	if planned {
+		fmt.Fprintf(&pomosToday, " · %d/%d tasks", m.finishedTasks(plan), len(plan.Tasks))
 	}
 	var pomos string
-	if m.config.DailyGoal > 0 && len(m.previous) >= m.config.DailyGoal {
+	if goal > 0 && completed >= goal {
 		pomos = FooterPomosGoal.Render(pomosToday.String())
 	} else {
 		pomos = FooterPomos.Render(pomosToday.String())
@@ -469,12 +908,13 @@ func (m Model) viewFooter() string {
 	helpMessage := FooterHelp.Render("? help")
 
 	w := lipgloss.Width
-	spacerWidth := max(0, m.width-w(state)-w(timer)-w(errMessage)-w(pomos)-w(saveState)-w(helpMessage))
+	spacerWidth := max(0, m.width-w(state)-w(timer)-w(interruptions)-w(errMessage)-w(pomos)-w(saveState)-w(helpMessage))
 	spacer := strings.Repeat(" ", spacerWidth)
 
 	return lipgloss.JoinHorizontal(lipgloss.Top,
 		state,
 		timer,
+		interruptions,
 		errMessage,
 		spacer,
 		pomos,
@@ -494,6 +934,7 @@ func (m Model) viewHelp() string {
 func (m *Model) InputNewTask(status pomo.Status) tea.Cmd {
 	m.mode = modeNewTask
 	m.editor.SetTask(pomo.Task{
+		ID:     pomo.NewTaskID(),
 		Status: status,
 		Name:   "",
 		Notes:  "",
@@ -508,10 +949,34 @@ func (m *Model) EditTask(task pomo.Task) tea.Cmd {
 }
 
 func (m Model) FullHelp() [][]key.Binding {
+	switch m.mode {
+	case modeHistory:
+		return append([][]key.Binding{{m.KeyMap.Quit}}, m.history.KeyMap.FullHelp()...)
+	case modeArchive:
+		return append([][]key.Binding{{m.KeyMap.Quit}}, m.archive.KeyMap.FullHelp()...)
+	case modePlan:
+		return append([][]key.Binding{{m.KeyMap.Quit}}, m.planner.KeyMap.FullHelp()...)
+	case modeStats:
+		return append([][]key.Binding{{m.KeyMap.Quit}}, m.stats.KeyMap.FullHelp()...)
+	case modeTimeTravel:
+		return append([][]key.Binding{{m.KeyMap.Quit}}, m.travel.KeyMap.FullHelp()...)
+	}
 	return append(m.KeyMap.FullHelp(), m.kanban.KeyMap.FullHelp()...)
 }
 
 func (m Model) ShortHelp() []key.Binding {
+	switch m.mode {
+	case modeHistory:
+		return append([]key.Binding{m.KeyMap.Quit}, m.history.KeyMap.ShortHelp()...)
+	case modeArchive:
+		return append([]key.Binding{m.KeyMap.Quit}, m.archive.KeyMap.ShortHelp()...)
+	case modePlan:
+		return append([]key.Binding{m.KeyMap.Quit}, m.planner.KeyMap.ShortHelp()...)
+	case modeStats:
+		return append([]key.Binding{m.KeyMap.Quit}, m.stats.KeyMap.ShortHelp()...)
+	case modeTimeTravel:
+		return append([]key.Binding{m.KeyMap.Quit}, m.travel.KeyMap.ShortHelp()...)
+	}
 	return append(m.KeyMap.ShortHelp(), m.kanban.KeyMap.ShortHelp()...)
 }
 
@@ -535,6 +1000,11 @@ func (m *Model) layout() {
 	m.editor.SetMaxSize(m.width-2, kanbanHeight-2)
 
 	m.kanban.SetSize(m.width, kanbanHeight)
+	m.history.SetSize(m.width, kanbanHeight)
+	m.archive.SetSize(m.width, kanbanHeight)
+	m.planner.SetSize(m.width, kanbanHeight)
+	m.stats.SetSize(m.width, kanbanHeight)
+	m.travel.SetSize(m.width, kanbanHeight)
 }
 
 func (m Model) loadState() tea.Cmd {
@@ -543,66 +1013,171 @@ func (m Model) loadState() tea.Cmd {
 		return message.Err(err)
 	}
 
-	now := time.Now()
-	year, month, day := now.Date()
-	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	if assignTaskIDs(current.Tasks) {
+		err = m.store.SaveCurrent(current)
+		if err != nil {
+			return message.Err(err)
+		}
+	}
 
-	previous, err := m.store.List(today)
+	pomos, err := m.store.List(m.today())
 	if err != nil {
 		return message.Err(err)
 	}
 
-	return message.LoadState(current, previous)
-}
+	// cancelled and interrupted pomodoros don't count towards the day's total
+	var previous []pomo.Pomo
+	for _, p := range pomos {
+		if p.Completed() {
+			previous = append(previous, p)
+		}
+	}
 
-func (m *Model) saveState() tea.Cmd {
-	err := m.store.SaveCurrent(m.current)
+	actuals, err := m.store.Actuals()
 	if err != nil {
 		return message.Err(err)
 	}
-	return message.LoadState(m.current, m.previous)
+
+	return message.LoadState(current, previous, actuals)
 }
 
-func (m *Model) completePomo() tea.Cmd {
-	var incomplete, workedOn []pomo.Task
-	for _, task := range m.kanban.Tasks() {
-		if task.Status < pomo.Done {
-			incomplete = append(incomplete, task)
+// loadPlan loads today's plan. The planning screen is opened on the first
+// launch of the day, before any pomodoros are started and until a plan is
+// saved.
+func (m Model) loadPlan() tea.Cmd {
+	today := m.today()
+	plan, ok, err := m.store.GetPlan(today)
+	if err != nil {
+		return message.Err(err)
+	}
+	if ok {
+		tasks, err := m.store.ListArchive()
+		if err != nil {
+			return message.Err(err)
+		}
+		planned := make(map[string]bool, len(plan.Tasks))
+		for _, task := range plan.Tasks {
+			planned[task.ID] = true
 		}
-		if task.Status > pomo.Todo {
-			workedOn = append(workedOn, task)
+		var archived []string
+		for _, task := range tasks {
+			if planned[task.ID] {
+				archived = append(archived, task.ID)
+			}
 		}
+		return message.LoadPlan(plan, archived, false)
 	}
 
-	completed := pomo.Pomo{
-		Start: m.current.Start,
-		End:   m.current.End,
-		Tasks: workedOn,
-	}
-	err := m.store.SavePomo(completed)
+	pomos, err := m.store.List(today)
 	if err != nil {
-		return message.Err(fmt.Errorf("saving pomodoro: %w", err))
+		return message.Err(err)
 	}
+	return message.LoadPlan(plan, nil, len(pomos) == 0)
+}
 
-	m.previous = append(m.previous, completed)
+// assignTaskIDs assigns an ID to any task saved before task IDs were
+// introduced. Returns whether any task was modified.
+func assignTaskIDs(tasks []pomo.Task) bool {
+	var modified bool
+	for i := range tasks {
+		if tasks[i].ID == "" {
+			tasks[i].ID = pomo.NewTaskID()
+			modified = true
+		}
+	}
+	return modified
+}
 
-	m.pomoState = pomoBreak
-	duration := m.config.BreakDuration
-	if len(m.previous)%4 == 0 {
-		m.pomoState = pomoLongBreak
-		duration = m.config.LongBreakDuration
+func (m *Model) saveState() tea.Cmd {
+	current := m.session.Current()
+	err := m.store.SaveCurrent(current)
+	if err != nil {
+		return message.Err(err)
 	}
-	breakEnd := time.Now().Add(duration)
-	m.current.Start = breakEnd
-	m.current.End = time.Time{}
-	m.current.Tasks = incomplete
+	return message.LoadState(current, m.session.Completed(), nil)
+}
 
-	err = m.store.SaveCurrent(m.current)
+// transition applies the event to the pomodoro session. Events that are not
+// allowed in the current state are ignored.
+func (m *Model) transition(e session.Event) tea.Cmd {
+	m.session.SetTasks(m.kanban.Tasks())
+	next := m.session
+	result, err := next.Handle(e)
 	if err != nil {
-		return message.Err(fmt.Errorf("updating current pomodoro: %w", err))
+		log.Debug("ignoring event", "event", e, "err", err)
+		return nil
 	}
+	err = m.saveFinished(result)
+	if err != nil {
+		return message.Err(err)
+	}
+	m.session = next
+	return m.apply(result)
+}
 
-	return tea.Batch(m.timer.Start(breakEnd), m.kanban.SetTasks(incomplete))
+// saveFinished saves a completed or abandoned pomodoro to history. It is saved
+// before the session moves on, so a pomodoro that can't be saved isn't lost.
+func (m *Model) saveFinished(result session.Result) error {
+	if result.Finished == nil {
+		return nil
+	}
+	err := m.store.SavePomo(*result.Finished)
+	if err != nil {
+		return fmt.Errorf("saving %s pomodoro: %w", result.Finished.Outcome, err)
+	}
+	return nil
+}
+
+// apply archives the done tasks of a completed pomodoro and brings the board
+// and timer in line with the session.
+func (m *Model) apply(result session.Result) tea.Cmd {
+	var cmds []tea.Cmd
+	if result.Finished != nil {
+		if len(result.Archived) > 0 {
+			err := m.store.ArchiveTasks(result.Finished.End, result.Archived)
+			if err != nil {
+				// keep the done tasks on the board rather than lose them
+				m.session.SetTasks(append(m.session.Current().Tasks, result.Archived...))
+				cmds = append(cmds, message.Err(fmt.Errorf("archiving done tasks: %w", err)))
+			} else {
+				m.markArchived(result.Archived)
+			}
+		}
+		if result.Finished.Completed() {
+			// done tasks have left the board, and can't be brought back
+			m.kanban.ClearHistory()
+			cmds = append(cmds, m.countActuals(*result.Finished))
+		}
+		cmds = append(cmds, m.kanban.SetTasks(m.session.Current().Tasks))
+	}
+	cmds = append(cmds, m.syncTimer())
+	if result.Changed {
+		cmds = append(cmds, m.saveState())
+	}
+	return tea.Batch(cmds...)
+}
+
+// countActuals adds the completed pomodoro to the actuals of the tasks worked
+// on during it.
+func (m *Model) countActuals(p pomo.Pomo) tea.Cmd {
+	actuals := make(map[string]int, len(m.actuals))
+	maps.Copy(actuals, m.actuals)
+	for _, task := range p.Tasks {
+		actuals[task.ID]++
+	}
+	m.actuals = actuals
+	return m.kanban.SetActuals(actuals)
+}
+
+// syncTimer starts, pauses or resets the timer to match the session.
+func (m *Model) syncTimer() tea.Cmd {
+	if m.session.Paused() {
+		return m.timer.Pause(m.session.Remaining())
+	}
+	if end, ok := m.session.End(); ok {
+		return m.timer.Start(end)
+	}
+	return m.timer.Reset()
 }
 
 type debounceSaveMsg struct {
@@ -614,6 +1189,13 @@ type clearErrMsg struct{}
 type DeleteTaskMsg struct{}
 
 type StartPomoMsg struct{}
-type CancelPomoMsg struct{}
+type CancelPomoMsg struct {
+	Outcome pomo.Outcome
+	Reason  string
+}
 type CompletePomoMsg struct{}
+type LogInterruptionMsg struct {
+	Kind pomo.InterruptionKind
+	Note string
+}
 type CancelBreakMsg struct{}
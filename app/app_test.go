@@ -0,0 +1,424 @@
+package app_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/app"
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/config"
+	"github.com/qualidafial/pomo/control"
+	"github.com/qualidafial/pomo/message"
+	"github.com/qualidafial/pomo/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+// launch starts the app against a store holding the given current pomodoro,
+// and loads its state and plan.
+func launch(t *testing.T, cfg config.Config, current pomo.Pomo) (app.Model, store.Storage) {
+	t.Helper()
+
+	c := clock.NewFake(now)
+	s := store.NewMemory(c)
+	require.NoError(t, s.SaveCurrent(current))
+	return start(t, cfg, s, c), s
+}
+
+// start starts the app against the given store, and loads its state and plan.
+func start(t *testing.T, cfg config.Config, s store.Storage, c clock.Clock) app.Model {
+	t.Helper()
+
+	var m tea.Model = app.New(cfg, s, c)
+	batch, ok := m.Init()().(tea.BatchMsg)
+	require.True(t, ok)
+	for _, cmd := range batch {
+		switch msg := cmd().(type) {
+		case message.LoadStateMsg, message.LoadPlanMsg:
+			m, _ = m.Update(msg)
+		}
+	}
+	return m.(app.Model)
+}
+
+// saveBoard modifies the board and runs the debounced save.
+func saveBoard(t *testing.T, c *clock.Fake, m app.Model) app.Model {
+	t.Helper()
+
+	next, cmd := m.Update(message.TasksModifiedMsg{})
+	batch, ok := cmd().(tea.BatchMsg)
+	require.True(t, ok)
+	var msg tea.Msg
+	c.Run(func() {
+		msg = batch[0]()
+	})
+	next, _ = next.Update(msg)
+	return next.(app.Model)
+}
+
+// failingStore is a store that fails to save pomodoros to history, or to
+// archive tasks.
+type failingStore struct {
+	store.Storage
+	savePomo bool
+	archive  bool
+}
+
+func (s failingStore) SavePomo(p pomo.Pomo) error {
+	if s.savePomo {
+		return errors.New("disk full")
+	}
+	return s.Storage.SavePomo(p)
+}
+
+func (s failingStore) ArchiveTasks(at time.Time, tasks []pomo.Task) error {
+	if s.archive {
+		return errors.New("disk full")
+	}
+	return s.Storage.ArchiveTasks(at, tasks)
+}
+
+func status(t *testing.T, m app.Model) control.Status {
+	t.Helper()
+
+	req, reply := control.NewRequestMsg(control.Request{
+		Verb: control.VerbStatus,
+	})
+	m.Update(req)
+	resp := <-reply
+	require.True(t, resp.OK, resp.Error)
+	return *resp.Status
+}
+
+func TestResume(t *testing.T) {
+	yesterday := now.AddDate(0, 0, -1)
+
+	tests := map[string]struct {
+		configure     func(*config.Config)
+		current       pomo.Pomo
+		wantState     string
+		wantPaused    bool
+		wantRemaining time.Duration
+		wantHistory   int
+	}{
+		"nothing running": {
+			wantState: "idle",
+		},
+		"pomodoro in progress": {
+			current: pomo.Pomo{
+				Start: now.Add(-10 * time.Minute),
+				End:   now.Add(15 * time.Minute),
+			},
+			wantState:     "active",
+			wantRemaining: 15 * time.Minute,
+		},
+		"paused pomodoro": {
+			current: pomo.Pomo{
+				Start:     now.Add(-2 * time.Hour),
+				End:       now.Add(-95 * time.Minute),
+				Remaining: 7 * time.Minute,
+				Pauses: []pomo.Pause{
+					{Start: now.Add(-113 * time.Minute)},
+				},
+			},
+			wantState:     "active",
+			wantPaused:    true,
+			wantRemaining: 7 * time.Minute,
+		},
+		"pomodoro ended while closed": {
+			current: pomo.Pomo{
+				Start: now.Add(-40 * time.Minute),
+				End:   now.Add(-15 * time.Minute),
+			},
+			wantState: "ended",
+		},
+		"break started automatically while closed": {
+			configure: func(cfg *config.Config) {
+				cfg.AutoStartBreak = true
+				cfg.BreakDuration = 20 * time.Minute
+			},
+			current: pomo.Pomo{
+				Start: now.Add(-40 * time.Minute),
+				End:   now.Add(-15 * time.Minute),
+			},
+			wantState:     "break",
+			wantRemaining: 5 * time.Minute,
+			wantHistory:   1,
+		},
+		"break in progress": {
+			current: pomo.Pomo{
+				Start: now.Add(3 * time.Minute),
+			},
+			wantState:     "break",
+			wantRemaining: 3 * time.Minute,
+		},
+		"break ended earlier today": {
+			current: pomo.Pomo{
+				Start: now.Add(-time.Hour),
+			},
+			wantState: "break-ended",
+		},
+		"break ended yesterday": {
+			current: pomo.Pomo{
+				Start: yesterday.Add(5 * time.Minute),
+			},
+			wantState: "idle",
+		},
+		"pomodoro ended yesterday": {
+			current: pomo.Pomo{
+				Start: yesterday.Add(-25 * time.Minute),
+				End:   yesterday,
+			},
+			wantState: "ended",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.Default()
+			if tc.configure != nil {
+				tc.configure(&cfg)
+			}
+			m, s := launch(t, cfg, tc.current)
+
+			got := status(t, m)
+			assert.Equal(t, tc.wantState, got.State)
+			assert.Equal(t, tc.wantPaused, got.Paused)
+			assert.Equal(t, int(tc.wantRemaining/time.Second), got.Remaining)
+
+			history, err := s.List(now.AddDate(0, 0, -1))
+			require.NoError(t, err)
+			assert.Len(t, history, tc.wantHistory)
+		})
+	}
+}
+
+func TestCompleteSaveFails(t *testing.T) {
+	ended := pomo.Pomo{
+		Start: now.Add(-40 * time.Minute),
+		End:   now.Add(-15 * time.Minute),
+		Tasks: []pomo.Task{
+			{ID: "3f2a9c1e7b4d6a05", Status: pomo.Done, Name: "Paint the fence"},
+		},
+	}
+
+	tests := map[string]struct {
+		configure func(*config.Config)
+		complete  bool
+	}{
+		"completed by the user": {
+			complete: true,
+		},
+		"break started automatically while closed": {
+			configure: func(cfg *config.Config) {
+				cfg.AutoStartBreak = true
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.Default()
+			if tc.configure != nil {
+				tc.configure(&cfg)
+			}
+			c := clock.NewFake(now)
+			mem := store.NewMemory(c)
+			require.NoError(t, mem.SaveCurrent(ended))
+
+			m := start(t, cfg, failingStore{Storage: mem, savePomo: true}, c)
+			if tc.complete {
+				next, cmd := m.Update(app.CompletePomoMsg{})
+				m = next.(app.Model)
+				require.NotNil(t, cmd)
+			}
+
+			// the pomodoro is still waiting to be completed
+			assert.Equal(t, "ended", status(t, m).State)
+
+			// and saving the board keeps it
+			saveBoard(t, c, m)
+			current, err := mem.GetCurrent()
+			require.NoError(t, err)
+			assert.Equal(t, ended, current)
+		})
+	}
+}
+
+func TestCompleteArchiveFails(t *testing.T) {
+	fence := pomo.Task{ID: "3f2a9c1e7b4d6a05", Status: pomo.Done, Name: "Paint the fence"}
+	c := clock.NewFake(now)
+	mem := store.NewMemory(c)
+	require.NoError(t, mem.SaveCurrent(pomo.Pomo{
+		Start: now.Add(-40 * time.Minute),
+		End:   now.Add(-15 * time.Minute),
+		Tasks: []pomo.Task{fence},
+	}))
+
+	m := start(t, config.Default(), failingStore{Storage: mem, archive: true}, c)
+	next, _ := m.Update(app.CompletePomoMsg{})
+	m = next.(app.Model)
+
+	// the pomodoro is completed, but the done task stays on the board
+	assert.Equal(t, "break", status(t, m).State)
+	pomos, err := mem.List()
+	require.NoError(t, err)
+	assert.Len(t, pomos, 1)
+	current, err := mem.GetCurrent()
+	require.NoError(t, err)
+	assert.Equal(t, []pomo.Task{fence}, current.Tasks)
+	assert.Equal(t, now.Add(5*time.Minute), current.Start)
+}
+
+func TestRestoreTask(t *testing.T) {
+	m, s := launch(t, config.Default(), pomo.Pomo{})
+	next, _ := m.Update(message.ClosePlanMsg{})
+	m = next.(app.Model)
+
+	fence := pomo.Task{ID: "3f2a9c1e7b4d6a05", Status: pomo.Done, Name: "Paint the fence"}
+	require.NoError(t, s.ArchiveTasks(now, []pomo.Task{fence}))
+	archived, err := s.ListArchive()
+	require.NoError(t, err)
+
+	m.Update(message.RestoreTaskMsg{Task: archived[0]})
+
+	// the task is saved to the board right away
+	current, err := s.GetCurrent()
+	require.NoError(t, err)
+	require.Len(t, current.Tasks, 1)
+	assert.Equal(t, fence.ID, current.Tasks[0].ID)
+	assert.Equal(t, pomo.Todo, current.Tasks[0].Status)
+	archived, err = s.ListArchive()
+	require.NoError(t, err)
+	assert.Empty(t, archived)
+}
+
+func TestPlan(t *testing.T) {
+	today := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	m, s := launch(t, config.Default(), pomo.Pomo{
+		Tasks: []pomo.Task{
+			{ID: "a", Status: pomo.Todo, Name: "Paint the fence"},
+			{ID: "b", Status: pomo.Doing, Name: "Wax the car", Estimate: 1},
+			{ID: "c", Status: pomo.Done, Name: "Sand the floor"},
+		},
+	})
+
+	// the planning screen opens on the first launch of the day
+	var model tea.Model = m
+	assert.Contains(t, model.View(), "Plan for Tue Mar 5")
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'+'}},
+		{Type: tea.KeyRunes, Runes: []rune{'+'}},
+		{Type: tea.KeyDown},
+		{Type: tea.KeyRunes, Runes: []rune{' '}},
+		{Type: tea.KeyEnter},
+	}
+	var cmd tea.Cmd
+	for _, key := range keys {
+		model, cmd = model.Update(key)
+	}
+	require.NotNil(t, cmd)
+	model, _ = model.Update(cmd())
+
+	plan, ok, err := s.GetPlan(today)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, 3, plan.Estimate())
+	var names []string
+	for _, task := range plan.Tasks {
+		names = append(names, task.Name)
+	}
+	assert.Equal(t, []string{"Paint the fence", "Wax the car"}, names)
+
+	// the footer shows progress against the plan
+	assert.Contains(t, model.View(), "0/3 pomos · 0/2 tasks")
+}
+
+func TestPlan_Progress(t *testing.T) {
+	today := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	fence := pomo.Task{ID: "a", Status: pomo.Todo, Name: "Paint the fence"}
+	car := pomo.Task{ID: "b", Status: pomo.Done, Name: "Wax the car"}
+	floor := pomo.Task{ID: "c", Status: pomo.Done, Name: "Sand the floor"}
+
+	c := clock.NewFake(now)
+	s := store.NewMemory(c)
+	require.NoError(t, s.SavePlan(pomo.Plan{Day: today, Tasks: []pomo.Task{fence, car, floor}}))
+	require.NoError(t, s.ArchiveTasks(now, []pomo.Task{floor}))
+	// the fence was deleted rather than done
+	require.NoError(t, s.SaveCurrent(pomo.Pomo{Tasks: []pomo.Task{car}}))
+
+	var m tea.Model = start(t, config.Default(), s, c)
+	assert.Contains(t, m.View(), "0 pomos · 2/3 tasks")
+}
+
+func TestTimeTravel(t *testing.T) {
+	m, _ := launch(t, config.Default(), pomo.Pomo{
+		Tasks: []pomo.Task{
+			{ID: "a", Status: pomo.Todo, Name: "Paint the fence"},
+			{ID: "b", Status: pomo.Doing, Name: "Wax the car"},
+		},
+	})
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	model, _ = model.Update(message.ClosePlanMsg{})
+	model, _ = model.Update(message.NewTaskMsg{Status: pomo.Todo, Name: "Sand the deck"})
+	assert.Contains(t, model.View(), "Sand the deck")
+
+	// opening time travel loads the list of snapshots, then the newest one
+	var cmd tea.Cmd
+	model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	require.NotNil(t, cmd)
+	model, cmd = model.Update(cmd())
+	require.NotNil(t, cmd)
+	model, _ = model.Update(cmd())
+	view := model.View()
+	assert.Contains(t, view, "Time travel: 1 snapshot ")
+	assert.Contains(t, view, "- Sand the deck")
+
+	model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	require.NotNil(t, cmd)
+	model, _ = model.Update(cmd())
+	view = model.View()
+	assert.Contains(t, view, "Wax the car")
+	assert.NotContains(t, view, "Sand the deck")
+}
+
+func TestRestoreSnapshot_AssignsTaskIDs(t *testing.T) {
+	c := clock.NewFake(now)
+	s := store.NewMemory(c)
+	m := start(t, config.Default(), s, c)
+
+	// snapshots taken before task IDs were introduced
+	next, _ := m.Update(message.RestoreSnapshotMsg{
+		Time: now.Add(-time.Hour),
+		Snapshot: pomo.Pomo{Tasks: []pomo.Task{
+			{Status: pomo.Todo, Name: "Paint the fence"},
+			{Status: pomo.Done, Name: "Wax the car"},
+		}},
+	})
+	saveBoard(t, c, next.(app.Model))
+
+	current, err := s.GetCurrent()
+	require.NoError(t, err)
+	require.Len(t, current.Tasks, 2)
+	for _, task := range current.Tasks {
+		assert.NotEmpty(t, task.ID, task.Name)
+	}
+}
+
+func TestControl_ExpiredRequest(t *testing.T) {
+	m, _ := launch(t, config.Default(), pomo.Pomo{})
+
+	// the client gave up waiting before the app got to the request
+	req, reply := control.NewRequestMsg(control.Request{Verb: control.VerbStart})
+	require.True(t, req.Expire())
+	next, cmd := m.Update(req)
+	assert.Nil(t, cmd)
+	assert.Empty(t, reply)
+	assert.Equal(t, "idle", status(t, next.(app.Model)).State)
+}
@@ -9,14 +9,26 @@ type KeyMap struct {
 
 	Quit key.Binding
 
-	StartPomo   key.Binding
-	CancelPomo  key.Binding
-	StartBreak  key.Binding
-	CancelBreak key.Binding
+	StartPomo     key.Binding
+	CancelPomo    key.Binding
+	InterruptPomo key.Binding
+	StartBreak    key.Binding
+	CancelBreak   key.Binding
+	Pause         key.Binding
+	Resume        key.Binding
+
+	LogInternal key.Binding
+	LogExternal key.Binding
 
 	NewTask    key.Binding
 	EditTask   key.Binding
 	DeleteTask key.Binding
+
+	History    key.Binding
+	Archive    key.Binding
+	Plan       key.Binding
+	Stats      key.Binding
+	TimeTravel key.Binding
 }
 
 func DefaultKeyMap() KeyMap {
@@ -39,6 +51,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("p"),
 			key.WithHelp("p", "cancel pomo"),
 		),
+		InterruptPomo: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "interrupted"),
+		),
 		StartBreak: key.NewBinding(
 			key.WithKeys("p"),
 			key.WithHelp("p", "complete pomo and start break"),
@@ -47,6 +63,23 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("p"),
 			key.WithHelp("p", "cancel break"),
 		),
+		Pause: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "pause"),
+		),
+		Resume: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "resume"),
+		),
+
+		LogInternal: key.NewBinding(
+			key.WithKeys("'"),
+			key.WithHelp("'", "log internal interruption"),
+		),
+		LogExternal: key.NewBinding(
+			key.WithKeys("\""),
+			key.WithHelp("\"", "log external interruption"),
+		),
 
 		NewTask: key.NewBinding(
 			key.WithKeys("+", "insert"),
@@ -60,6 +93,27 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "edit task"),
 		),
+
+		History: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "view history"),
+		),
+		Archive: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "view archive"),
+		),
+		Plan: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "plan the day"),
+		),
+		Stats: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "view stats"),
+		),
+		TimeTravel: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "time travel"),
+		),
 	}
 }
 
@@ -69,14 +123,26 @@ func (m KeyMap) FullHelp() [][]key.Binding {
 			m.Quit,
 			m.StartPomo,
 			m.CancelPomo,
+			m.InterruptPomo,
 			m.StartBreak,
 			m.CancelBreak,
+			m.Pause,
+			m.Resume,
+			m.LogInternal,
+			m.LogExternal,
 		},
 		{
 			m.NewTask,
 			m.DeleteTask,
 			m.EditTask,
 		},
+		{
+			m.History,
+			m.Archive,
+			m.Plan,
+			m.Stats,
+			m.TimeTravel,
+		},
 	}
 }
 
@@ -85,10 +151,18 @@ func (m KeyMap) ShortHelp() []key.Binding {
 		m.Quit,
 		m.StartPomo,
 		m.CancelPomo,
+		m.InterruptPomo,
 		m.StartBreak,
 		m.CancelBreak,
+		m.Pause,
+		m.Resume,
 		m.NewTask,
 		m.DeleteTask,
 		m.EditTask,
+		m.History,
+		m.Archive,
+		m.Plan,
+		m.Stats,
+		m.TimeTravel,
 	}
 }
@@ -26,6 +26,10 @@ var (
 			Padding(0, 1).
 			Background(lipgloss.Color("235")).
 			Foreground(lipgloss.Color("248"))
+	FooterInterruptions = lipgloss.NewStyle().
+				Padding(0, 1).
+				Background(lipgloss.Color("236")).
+				Foreground(lipgloss.Color("214"))
 	FooterError = lipgloss.NewStyle().
 			Padding(0, 1).
 			Bold(true).
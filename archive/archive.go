@@ -0,0 +1,160 @@
+// Package archive provides a searchable browser of archived tasks.
+package archive
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/message"
+)
+
+const timeFormat = "Mon Jan 2, 2006 15:04"
+
+type item struct {
+	pomo.ArchivedTask
+}
+
+func (i item) Title() string {
+	return i.Name
+}
+
+func (i item) Description() string {
+	description := "archived " + i.ArchivedAt.Local().Format(timeFormat)
+	if len(i.Tags) > 0 {
+		description += " · " + strings.Join(i.Tags, " ")
+	}
+	return description
+}
+
+func (i item) FilterValue() string {
+	return i.Name + " " + i.Notes + " " + strings.Join(i.Tags, " ")
+}
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	width  int
+	height int
+
+	list list.Model
+}
+
+func New() Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.SetShowTitle(false)
+	l.SetShowHelp(false)
+	l.SetStatusBarItemName("task", "tasks")
+	l.DisableQuitKeybindings()
+
+	return Model{
+		KeyMap: DefaultKeyMap(),
+		Styles: DefaultStyles(),
+
+		list: l,
+	}
+}
+
+// Open returns a command to load the archived tasks.
+func (m Model) Open() tea.Cmd {
+	return message.LoadArchive
+}
+
+// SetTasks sets the archived tasks displayed, keeping the current filter and
+// selection where possible.
+func (m *Model) SetTasks(tasks []pomo.ArchivedTask) tea.Cmd {
+	items := make([]list.Item, len(tasks))
+	for i, task := range tasks {
+		items[i] = item{
+			ArchivedTask: task,
+		}
+	}
+	cmd := m.list.SetItems(items)
+	m.updateKeys()
+	return cmd
+}
+
+// Task returns the selected archived task.
+func (m Model) Task() (pomo.ArchivedTask, bool) {
+	selected, ok := m.list.SelectedItem().(item)
+	return selected.ArchivedTask, ok
+}
+
+// Filtering returns whether the user is typing a search term, in which case
+// all keys are handled by the archive.
+func (m Model) Filtering() bool {
+	return m.list.SettingFilter()
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case m.list.SettingFilter():
+			m.list, cmd = m.list.Update(msg)
+		case key.Matches(msg, m.KeyMap.Restore):
+			task, ok := m.Task()
+			if ok {
+				cmd = message.RestoreTask(task)
+			}
+		case key.Matches(msg, m.KeyMap.Close) && !m.list.IsFiltered():
+			cmd = message.CloseArchive
+		default:
+			m.list, cmd = m.list.Update(msg)
+		}
+	default:
+		m.list, cmd = m.list.Update(msg)
+	}
+
+	m.updateKeys()
+
+	return m, cmd
+}
+
+func (m *Model) updateKeys() {
+	_, selection := m.Task()
+	filtering := m.list.SettingFilter()
+	filtered := m.list.IsFiltered()
+
+	m.KeyMap.Up.SetEnabled(!filtering && m.list.Index() > 0)
+	m.KeyMap.Down.SetEnabled(!filtering && m.list.Index()+1 < len(m.list.VisibleItems()))
+	m.KeyMap.Search.SetEnabled(!filtering && len(m.list.Items()) > 0)
+	m.KeyMap.ClearSearch.SetEnabled(filtering || filtered)
+	m.KeyMap.Restore.SetEnabled(!filtering && selection)
+	m.KeyMap.Close.SetEnabled(!filtering && !filtered)
+}
+
+func (m *Model) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+func (m Model) View() string {
+	title := m.Styles.Title.Render("Archive: " + pomo.Plural(len(m.list.Items()), "task"))
+
+	frameWidth, frameHeight := m.Styles.Frame.GetFrameSize()
+	height := max(0, m.height-lipgloss.Height(title))
+	m.list.SetSize(max(0, m.width-frameWidth), max(0, height-frameHeight))
+
+	var body string
+	if len(m.list.Items()) == 0 {
+		body = m.Styles.Empty.Render("No archived tasks")
+	} else {
+		body = m.list.View()
+	}
+
+	frame := m.Styles.Frame.
+		Width(max(0, m.width-m.Styles.Frame.GetHorizontalBorderSize())).
+		Height(max(0, height-m.Styles.Frame.GetVerticalBorderSize()))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		frame.Render(body),
+	)
+}
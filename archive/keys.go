@@ -0,0 +1,71 @@
+package archive
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type KeyMap struct {
+	Up          key.Binding
+	Down        key.Binding
+	Search      key.Binding
+	ClearSearch key.Binding
+	Restore     key.Binding
+	Close       key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "previous task"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "next task"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		ClearSearch: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear search"),
+		),
+		Restore: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "restore to do"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "a"),
+			key.WithHelp("esc", "close archive"),
+		),
+	}
+}
+
+func (m KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			m.Up,
+			m.Down,
+		},
+		{
+			m.Search,
+			m.ClearSearch,
+			m.Restore,
+		},
+		{
+			m.Close,
+		},
+	}
+}
+
+func (m KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.Up,
+		m.Down,
+		m.Search,
+		m.ClearSearch,
+		m.Restore,
+		m.Close,
+	}
+}
@@ -0,0 +1,28 @@
+package archive
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/qualidafial/pomo/color"
+)
+
+type Styles struct {
+	Title lipgloss.Style
+	Frame lipgloss.Style
+	Empty lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Padding(0, 1).
+			Background(lipgloss.Color("62")).
+			Foreground(lipgloss.Color("230")),
+		Frame: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")),
+		Empty: lipgloss.NewStyle().
+			Padding(0, 0, 0, 2).
+			Foreground(color.Gray),
+	}
+}
@@ -0,0 +1,145 @@
+// Package clock provides the current time to components that depend on it, so
+// that tests can control the passage of time.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real is the system clock.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Until returns the duration until t according to the clock.
+func Until(c Clock, t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+// Fake is a clock that only moves when told to. Waits on a fake clock end when
+// the clock is advanced past them.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []waiter
+	// blocked is signalled when a wait starts
+	blocked chan struct{}
+}
+
+type waiter struct {
+	until time.Time
+	ch    chan time.Time
+}
+
+// NewFake returns a fake clock set to the given time.
+func NewFake(now time.Time) *Fake {
+	return &Fake{
+		now:     now,
+		blocked: make(chan struct{}, 1),
+	}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set sets the clock to the given time, ending the waits that are due.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+	f.wake()
+}
+
+// Advance moves the clock forward by the given duration, ending the waits
+// that are due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	f.wake()
+}
+
+// After returns a channel that receives the time once the clock has been
+// advanced by the given duration.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	f.waiters = append(f.waiters, waiter{
+		until: f.now.Add(d),
+		ch:    ch,
+	})
+	f.wake()
+
+	select {
+	case f.blocked <- struct{}{}:
+	default:
+	}
+	return ch
+}
+
+// Run calls fn and returns once it returns. Whenever fn waits on the clock,
+// the clock is advanced to the end of the earliest wait, so code that waits,
+// even in a loop, runs without delay.
+func (f *Fake) Run(fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		case <-f.blocked:
+			f.mu.Lock()
+			if len(f.waiters) > 0 {
+				next := f.waiters[0].until
+				for _, w := range f.waiters[1:] {
+					if w.until.Before(next) {
+						next = w.until
+					}
+				}
+				if next.After(f.now) {
+					f.now = next
+				}
+				f.wake()
+			}
+			f.mu.Unlock()
+		}
+	}
+}
+
+// wake ends the waits that are due. The lock must be held.
+func (f *Fake) wake() {
+	waiters := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.until.After(f.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.ch <- f.now
+	}
+	f.waiters = waiters
+}
@@ -0,0 +1,42 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qualidafial/pomo/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+func TestFake_After(t *testing.T) {
+	c := clock.NewFake(start)
+
+	ch := c.After(time.Minute)
+	assert.Equal(t, start, c.Now(), "waiting does not move the clock")
+	assert.Empty(t, ch)
+
+	c.Advance(59 * time.Second)
+	assert.Empty(t, ch)
+
+	c.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-ch)
+
+	assert.Equal(t, start.Add(time.Minute), <-c.After(0))
+}
+
+func TestFake_Run(t *testing.T) {
+	c := clock.NewFake(start)
+
+	var ticks int
+	c.Run(func() {
+		for range 3 {
+			<-c.After(time.Second)
+			ticks++
+		}
+	})
+
+	assert.Equal(t, 3, ticks)
+	assert.Equal(t, start.Add(3*time.Second), c.Now())
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/qualidafial/pomo/control"
+)
+
+func runCtl(args []string, socket string) error {
+	if len(args) == 0 {
+		return errors.New("usage: pomo ctl status|start|cancel|complete|add-task")
+	}
+
+	req := control.Request{
+		Verb: args[0],
+	}
+
+	flags := flag.NewFlagSet("ctl "+req.Verb, flag.ContinueOnError)
+	switch req.Verb {
+	case control.VerbCancel:
+		flags.StringVar(&req.Reason, "reason", "", "reason for cancelling the pomodoro")
+	case control.VerbAddTask:
+		flags.StringVar(&req.Status, "status", "", "status or column name of the new task (default: the first column)")
+	}
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	if req.Verb == control.VerbAddTask {
+		req.Name = strings.Join(flags.Args(), " ")
+	}
+
+	resp, err := control.Do(socket, req)
+	if err != nil {
+		return err
+	}
+
+	if req.Verb == control.VerbStatus {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(resp.Status)
+	}
+	return nil
+}
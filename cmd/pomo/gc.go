@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/qualidafial/pomo/store"
+)
+
+func runGC(args []string, s store.Storage) error {
+	flags := flag.NewFlagSet("gc", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would be pruned without deleting anything")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	p, ok := s.(store.Pruner)
+	if !ok {
+		return fmt.Errorf("storage backend does not support pruning snapshots")
+	}
+	result, err := p.PruneSnapshots(*dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "pruned"
+	if *dryRun {
+		verb = "would prune"
+	}
+	_, err = fmt.Printf("%s %d of %d snapshots (%s), kept %d\n",
+		verb, result.Pruned, result.Pruned+result.Kept, formatBytes(result.Freed), result.Kept)
+	return err
+}
+
+// formatBytes formats a size in bytes using binary units, e.g. "1.5 MiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/config"
+	"github.com/qualidafial/pomo/control"
+	"github.com/qualidafial/pomo/store"
+)
+
+func runMigrate(args []string, dataDir, socket string, cfg config.Config) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	force := flags.Bool("force", false, "replace the contents of an existing database")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	// the running app would keep writing to the old backend
+	if _, err := control.Do(socket, control.Request{Verb: control.VerbStatus}); err == nil {
+		return errors.New("pomo is running: quit it first")
+	}
+
+	src, err := store.New(dataDir, clock.Real)
+	if err != nil {
+		return err
+	}
+	dst, err := store.OpenSQLite(sqlitePath(dataDir), clock.Real)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = dst.Close()
+	}()
+
+	existing, err := dst.Counts()
+	if err != nil {
+		return err
+	}
+	if existing != (store.Counts{}) && !*force {
+		return fmt.Errorf("%s already has records: use --force to replace them", sqlitePath(dataDir))
+	}
+
+	counts, err := dst.Import(src)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Migrated %s, %s, %s and %s to %s\n",
+		pomo.Plural(counts.Pomos, "pomodoro"),
+		pomo.Plural(counts.Snapshots, "snapshot"),
+		pomo.Plural(counts.Archived, "archived task"),
+		pomo.Plural(counts.Plans, "plan"),
+		sqlitePath(dataDir))
+	if cfg.Backend != string(store.BackendSQLite) {
+		fmt.Println("Set store.backend to sqlite in config.yaml to use it")
+	}
+	return nil
+}
@@ -2,13 +2,16 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
 	"github.com/qualidafial/pomo/app"
+	"github.com/qualidafial/pomo/clock"
 	"github.com/qualidafial/pomo/config"
+	"github.com/qualidafial/pomo/control"
 	"github.com/qualidafial/pomo/store"
 )
 
@@ -35,19 +38,114 @@ func main() {
 	log.SetOutput(f)
 	log.SetFormatter(log.TextFormatter)
 
-	s, err := store.New(dataDir)
+	cfg, err := config.Load(dataDir)
+	if err != nil {
+		log.Fatal(fmt.Errorf("loading configuration: %w", err))
+	}
+
+	s, err := openStorage(dataDir, cfg)
 	if err != nil {
 		log.Fatal(fmt.Errorf("creating pomo data store: %w", err))
 	}
+	if c, ok := s.(io.Closer); ok {
+		defer func() {
+			_ = c.Close()
+		}()
+	}
 
-	cfg, err := config.Load(dataDir)
+	if len(os.Args) > 1 {
+		err = runCommand(os.Args[1], os.Args[2:], dataDir, cfg, s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	p := tea.NewProgram(app.New(cfg, s, clock.Real))
+
+	if pruner, ok := s.(store.Pruner); ok {
+		stop := make(chan struct{})
+		defer close(stop)
+		go store.AutoPrune(pruner, clock.Real, stop)
+	}
+
+	srv, err := control.Listen(controlSocket(dataDir))
 	if err != nil {
-		log.Fatal(fmt.Errorf("loading configuration: %w", err))
+		log.Error("starting control socket", "err", err)
+	} else {
+		defer func() {
+			_ = srv.Close()
+		}()
+		go func() {
+			err := srv.Serve(p.Send)
+			if err != nil {
+				log.Error("serving control socket", "err", err)
+			}
+		}()
 	}
 
-	p := tea.NewProgram(app.New(cfg, s))
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("error: %v", err)
 		os.Exit(1)
 	}
 }
+
+// openStorage opens the storage backend selected in the config.
+func openStorage(dataDir string, cfg config.Config) (store.Storage, error) {
+	backend, err := store.ParseBackend(cfg.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	var s store.Storage
+	switch backend {
+	case store.BackendSQLite:
+		db, err := store.OpenSQLite(sqlitePath(dataDir), clock.Real)
+		if err != nil {
+			return nil, err
+		}
+		s = db
+	default:
+		fs, err := store.New(dataDir, clock.Real)
+		if err != nil {
+			return nil, err
+		}
+		s = fs
+	}
+	if p, ok := s.(store.Pruner); ok {
+		p.SetRetention(store.Retention{
+			Recent: cfg.Retention.KeepRecent,
+			Hourly: cfg.Retention.KeepHourly,
+			Daily:  cfg.Retention.KeepDaily,
+		})
+	}
+	return s, nil
+}
+
+func runCommand(name string, args []string, dataDir string, cfg config.Config, s store.Storage) error {
+	switch name {
+	case "report":
+		return runReport(args, s)
+	case "status":
+		return runStatus(os.Stdout, args, cfg, s, clock.Real.Now())
+	case "gc":
+		return runGC(args, s)
+	case "restore":
+		return runRestore(args, controlSocket(dataDir), s)
+	case "migrate":
+		return runMigrate(args, dataDir, controlSocket(dataDir), cfg)
+	case "ctl":
+		return runCtl(args, controlSocket(dataDir))
+	default:
+		return fmt.Errorf("unknown command: %s", name)
+	}
+}
+
+func controlSocket(dataDir string) string {
+	return filepath.Join(dataDir, "pomo.sock")
+}
+
+func sqlitePath(dataDir string) string {
+	return filepath.Join(dataDir, "pomo.db")
+}
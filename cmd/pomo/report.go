@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/report"
+	"github.com/qualidafial/pomo/store"
+)
+
+func runReport(args []string, s store.Storage) error {
+	now := clock.Real.Now()
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	from := flags.String("from", today.AddDate(0, 0, -6).Format(time.DateOnly), "first day of the report (YYYY-MM-DD)")
+	to := flags.String("to", today.Format(time.DateOnly), "last day of the report, inclusive (YYYY-MM-DD)")
+	groupBy := flags.String("group-by", "day", "group pomodoros by day, week or task")
+	format := flags.String("format", "table", "output format: table, json, csv or markdown")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	fromDate, err := time.ParseInLocation(time.DateOnly, *from, now.Location())
+	if err != nil {
+		return fmt.Errorf("parsing --from: %w", err)
+	}
+	toDate, err := time.ParseInLocation(time.DateOnly, *to, now.Location())
+	if err != nil {
+		return fmt.Errorf("parsing --to: %w", err)
+	}
+	g, err := report.ParseGroupBy(*groupBy)
+	if err != nil {
+		return err
+	}
+	f, err := report.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	pomos, err := s.List(fromDate, toDate.AddDate(0, 0, 1))
+	if err != nil {
+		return fmt.Errorf("listing pomodoros: %w", err)
+	}
+
+	return report.Write(os.Stdout, report.New(pomos, g), f)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/control"
+	"github.com/qualidafial/pomo/store"
+)
+
+// snapshotTimeFormats are the accepted formats of the point in time to
+// restore, in local time.
+var snapshotTimeFormats = []string{
+	time.DateTime,
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	time.DateOnly,
+}
+
+func runRestore(args []string, socket string, s store.Storage) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "show the changes to the board without restoring")
+	limit := flags.Int("limit", 20, "number of snapshots to list")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	times, err := s.Snapshots()
+	if err != nil {
+		return err
+	}
+	current, err := s.GetCurrent()
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return listSnapshots(s, times, current, *limit)
+	}
+
+	at, err := parseSnapshotTime(strings.Join(flags.Args(), " "))
+	if err != nil {
+		return err
+	}
+	// snapshots are listed newest first
+	var found bool
+	var t time.Time
+	for _, t = range times {
+		if !t.After(at) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("no snapshot at or before %s", at.Format(time.DateTime))
+	}
+
+	snapshot, err := s.GetSnapshot(t)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restoring the board to %s:\n", t.Local().Format(time.DateTime))
+	changes := pomo.DiffTasks(current.Tasks, snapshot.Tasks)
+	if len(changes) == 0 {
+		fmt.Println("No changes")
+		return nil
+	}
+	printChanges(changes)
+
+	if *dryRun {
+		return nil
+	}
+
+	// the running app would overwrite the restored board with its own
+	if _, err := control.Do(socket, control.Request{Verb: control.VerbStatus}); err == nil {
+		return errors.New("pomo is running: quit it first, or restore from the time travel view (t)")
+	}
+
+	current.Tasks = snapshot.Tasks
+	err = s.SaveCurrent(current)
+	if err != nil {
+		return fmt.Errorf("restoring snapshot: %w", err)
+	}
+	fmt.Printf("Restored %s\n", pomo.Plural(len(snapshot.Tasks), "task"))
+	return nil
+}
+
+func listSnapshots(s store.Storage, times []time.Time, current pomo.Pomo, limit int) error {
+	if len(times) == 0 {
+		fmt.Println("No snapshots")
+		return nil
+	}
+	for _, t := range times[:min(limit, len(times))] {
+		snapshot, err := s.GetSnapshot(t)
+		if err != nil {
+			fmt.Printf("%s  unreadable: %v\n", t.Local().Format(time.DateTime), err)
+			continue
+		}
+		changes := pomo.DiffTasks(current.Tasks, snapshot.Tasks)
+		fmt.Printf("%s  %s, %s\n", t.Local().Format(time.DateTime), pomo.Plural(len(snapshot.Tasks), "task"), pomo.Plural(len(changes), "change"))
+	}
+	if len(times) > limit {
+		fmt.Printf("… %d older snapshots\n", len(times)-limit)
+	}
+	return nil
+}
+
+func printChanges(changes []pomo.TaskChange) {
+	for _, change := range changes {
+		switch change.Kind {
+		case pomo.Added:
+			fmt.Printf("%s %s (%s)\n", change.Kind.Mark(), change.Task.Name, change.Task.Status)
+		case pomo.Removed:
+			fmt.Printf("%s %s\n", change.Kind.Mark(), change.Task.Name)
+		case pomo.Modified:
+			fmt.Printf("%s %s\n", change.Kind.Mark(), change.Task.Name)
+			for _, field := range change.Fields {
+				fmt.Printf("    %s\n", field)
+			}
+		}
+	}
+}
+
+func parseSnapshotTime(s string) (time.Time, error) {
+	for _, format := range snapshotTimeFormats {
+		t, err := time.ParseInLocation(format, s, time.Local)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("parsing time %q: expected YYYY-MM-DD [HH:MM[:SS]]", s)
+}
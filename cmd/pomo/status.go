@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/qualidafial/pomo/config"
+	"github.com/qualidafial/pomo/control"
+	"github.com/qualidafial/pomo/session"
+	"github.com/qualidafial/pomo/store"
+)
+
+const defaultStatusFormat = "🍅 {{.Clock}} {{.Summary}}"
+
+// statusData is the data available to the status --format template.
+type statusData struct {
+	control.Status
+	// Clock is the time remaining on the timer, formatted as in the app.
+	Clock string
+	// Summary describes the state as in the app footer, e.g. "pomo 3 in
+	// progress".
+	Summary string
+}
+
+func runStatus(w io.Writer, args []string, cfg config.Config, s store.Storage, now time.Time) error {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	format := flags.String("format", defaultStatusFormat, "Go text/template for the status line")
+	asJSON := flags.Bool("json", false, "print the status as JSON")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("status").Parse(*format)
+	if err != nil {
+		return fmt.Errorf("parsing --format: %w", err)
+	}
+
+	current, err := s.GetCurrent()
+	if err != nil {
+		return err
+	}
+
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	pomos, err := s.List(today)
+	if err != nil {
+		return fmt.Errorf("listing today's pomodoros: %w", err)
+	}
+	var completed int
+	for _, p := range pomos {
+		if p.Completed() {
+			completed++
+		}
+	}
+
+	state := session.Infer(current, completed, cfg.LongBreakEvery, now)
+	remaining := session.Remaining(current, state, now)
+
+	status := control.Status{
+		State:     state.String(),
+		Paused:    current.Paused(),
+		Pomo:      completed + 1,
+		Completed: completed,
+		DailyGoal: cfg.DailyGoal,
+		Remaining: int(remaining.Round(time.Second) / time.Second),
+	}
+	if end, ok := session.End(current, state); ok {
+		status.End = &end
+	}
+
+	if *asJSON {
+		return json.NewEncoder(w).Encode(status)
+	}
+
+	err = tmpl.Execute(w, statusData{
+		Status:  status,
+		Clock:   formatClock(remaining),
+		Summary: session.Describe(state, current.Paused(), completed),
+	})
+	if err != nil {
+		return fmt.Errorf("formatting status: %w", err)
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+// formatClock formats the duration as MM:SS or HH:MM:SS, rounding up to the
+// next whole second like the app timer.
+func formatClock(d time.Duration) string {
+	seconds := (d + time.Second - 1) / time.Second
+	minutes := seconds / 60
+	seconds %= 60
+	hours := minutes / 60
+	minutes %= 60
+	if hours > 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+}
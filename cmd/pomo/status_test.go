@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/config"
+	"github.com/qualidafial/pomo/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	cfg := config.Config{DailyGoal: 8, LongBreakEvery: 4}
+
+	s := store.NewMemory(clock.NewFake(now))
+	for i := range 2 {
+		start := now.Add(time.Duration(i-3) * time.Hour)
+		require.NoError(t, s.SavePomo(pomo.Pomo{Start: start, End: start.Add(25 * time.Minute)}))
+	}
+	require.NoError(t, s.SaveCurrent(pomo.Pomo{
+		Start: now.Add(-10 * time.Minute),
+		End:   now.Add(15*time.Minute + 30*time.Second),
+	}))
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "default format",
+			want: "🍅 15:30 pomo 3 in progress\n",
+		},
+		{
+			name: "custom format",
+			args: []string{"--format", "{{.State}} {{.Completed}}/{{.DailyGoal}} {{.Remaining}}s"},
+			want: "active 2/8 930s\n",
+		},
+		{
+			name: "json",
+			args: []string{"--json"},
+			want: `{"state":"active","pomo":3,"completed":2,"dailyGoal":8,"end":"2024-03-05T10:15:30Z","remaining":930}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			require.NoError(t, runStatus(&out, tt.args, cfg, s, now))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+
+	err := runStatus(&bytes.Buffer{}, []string{"--format", "{{.Clock"}, cfg, s, now)
+	assert.ErrorContains(t, err, "parsing --format")
+}
+
+func TestFormatClock(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "00:00"},
+		{time.Millisecond, "00:01"},
+		{59 * time.Second, "00:59"},
+		{25 * time.Minute, "25:00"},
+		{25*time.Minute - time.Millisecond, "25:00"},
+		{time.Hour, "01:00:00"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "01:02:03"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatClock(tt.d), tt.d.String())
+	}
+}
@@ -3,8 +3,10 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/qualidafial/pomo"
 	"github.com/spf13/viper"
 )
 
@@ -14,20 +16,180 @@ type Config struct {
 	PomodoroDuration  time.Duration
 	BreakDuration     time.Duration
 	LongBreakDuration time.Duration
+
+	// LongBreakEvery is the number of pomodoros completed in a day between long
+	// breaks. Zero disables long breaks.
+	LongBreakEvery int
+	// AutoStartBreak completes the pomodoro and starts the break as soon as the
+	// pomodoro timer ends, instead of waiting for the user to report tasks.
+	AutoStartBreak bool
+	// AutoStartPomodoro starts the next pomodoro as soon as the break ends.
+	AutoStartPomodoro bool
+
+	// Workflow is the set of columns on the task board.
+	Workflow pomo.Workflow
+
+	// Backend is where pomodoros and tasks are stored: yaml or sqlite.
+	Backend string
+	// Retention is the policy for pruning snapshots of the current pomodoro.
+	Retention Retention
 }
 
-func Load(path string) (Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(path)
+// Retention is the number of snapshots of the current pomodoro to keep: the
+// most recent ones, and the newest of each of the last hours and days that
+// have snapshots.
+type Retention struct {
+	KeepRecent int
+	KeepHourly int
+	KeepDaily  int
+}
+
+// column is a workflow column as written in the config file.
+type column struct {
+	Status   string `mapstructure:"status"`
+	Name     string `mapstructure:"name"`
+	Done     bool   `mapstructure:"done"`
+	WorkedOn bool   `mapstructure:"worked-on"`
+}
+
+// settings binds each config key to its field of the config, except for the
+// workflow columns.
+func settings(c *Config) map[string]any {
+	return map[string]any{
+		"pomo.daily-goal": &c.DailyGoal,
+
+		"timer.pomodoro":            &c.PomodoroDuration,
+		"timer.break":               &c.BreakDuration,
+		"timer.long-break":          &c.LongBreakDuration,
+		"timer.long-break-every":    &c.LongBreakEvery,
+		"timer.auto-start-break":    &c.AutoStartBreak,
+		"timer.auto-start-pomodoro": &c.AutoStartPomodoro,
+
+		"store.backend":               &c.Backend,
+		"store.retention.keep-recent": &c.Retention.KeepRecent,
+		"store.retention.keep-hourly": &c.Retention.KeepHourly,
+		"store.retention.keep-daily":  &c.Retention.KeepDaily,
+	}
+}
+
+// setDefaults sets the defaults of the config keys from the given config.
+func setDefaults(v *viper.Viper, c Config) {
+	for key, field := range settings(&c) {
+		switch field := field.(type) {
+		case *int:
+			v.SetDefault(key, *field)
+		case *bool:
+			v.SetDefault(key, *field)
+		case *string:
+			v.SetDefault(key, *field)
+		case *time.Duration:
+			v.SetDefault(key, formatDuration(*field))
+		default:
+			panic(fmt.Sprintf("unsupported type %T for config key %s", field, key))
+		}
+	}
+	v.SetDefault("workflow.columns", workflowColumns(c.Workflow))
+}
+
+// get reads the config keys into the given config.
+func get(v *viper.Viper, c *Config) {
+	for key, field := range settings(c) {
+		switch field := field.(type) {
+		case *int:
+			*field = v.GetInt(key)
+		case *bool:
+			*field = v.GetBool(key)
+		case *string:
+			*field = v.GetString(key)
+		case *time.Duration:
+			*field = v.GetDuration(key)
+		default:
+			panic(fmt.Sprintf("unsupported type %T for config key %s", field, key))
+		}
+	}
+}
+
+// formatDuration formats the duration for the config file, without trailing
+// zero units, e.g. "25m" rather than "25m0s".
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// workflowColumns returns the columns of the workflow as written in the config file.
+func workflowColumns(workflow pomo.Workflow) []map[string]any {
+	var columns []map[string]any
+	for _, col := range workflow.Columns {
+		c := map[string]any{
+			"status": string(col.Status),
+			"name":   col.Name,
+		}
+		if col.Done {
+			c["done"] = true
+		}
+		if col.WorkedOn {
+			c["worked-on"] = true
+		}
+		columns = append(columns, c)
+	}
+	return columns
+}
 
-	viper.SetDefault("pomo.daily-goal", 0)
+func loadWorkflow(v *viper.Viper) (pomo.Workflow, error) {
+	var columns []column
+	err := v.UnmarshalKey("workflow.columns", &columns)
+	if err != nil {
+		return pomo.Workflow{}, err
+	}
 
-	viper.SetDefault("timer.pomodoro", "25m")
-	viper.SetDefault("timer.break", "5m")
-	viper.SetDefault("timer.long-break", "15m")
+	var workflow pomo.Workflow
+	for _, col := range columns {
+		name := col.Name
+		if name == "" {
+			name = col.Status
+		}
+		workflow.Columns = append(workflow.Columns, pomo.Column{
+			Status:   pomo.Status(col.Status),
+			Name:     name,
+			Done:     col.Done,
+			WorkedOn: col.WorkedOn,
+		})
+	}
+	return workflow, workflow.Validate()
+}
 
-	err := viper.SafeWriteConfig()
+// Default returns the configuration of a new config file.
+func Default() Config {
+	return Config{
+		PomodoroDuration:  25 * time.Minute,
+		BreakDuration:     5 * time.Minute,
+		LongBreakDuration: 15 * time.Minute,
+		LongBreakEvery:    4,
+		Workflow:          pomo.DefaultWorkflow(),
+		Backend:           "yaml",
+		Retention: Retention{
+			KeepRecent: 100,
+			KeepHourly: 48,
+			KeepDaily:  90,
+		},
+	}
+}
+
+func Load(path string) (Config, error) {
+	v := viper.New()
+	v.SetConfigName("config")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(path)
+
+	setDefaults(v, Default())
+
+	err := v.SafeWriteConfig()
 	if err != nil {
 		var alreadyExistsErr viper.ConfigFileAlreadyExistsError
 		if !errors.As(err, &alreadyExistsErr) {
@@ -35,16 +197,19 @@ func Load(path string) (Config, error) {
 		}
 	}
 
-	err = viper.ReadInConfig()
+	err = v.ReadInConfig()
 	if err != nil {
 		return Config{}, fmt.Errorf("loading config: %w", err)
 	}
 
-	return Config{
-		DailyGoal: viper.GetInt("pomo.daily-goal"),
+	workflow, err := loadWorkflow(v)
+	if err != nil {
+		return Config{}, fmt.Errorf("loading workflow: %w", err)
+	}
 
-		PomodoroDuration:  viper.GetDuration("timer.pomodoro"),
-		BreakDuration:     viper.GetDuration("timer.break"),
-		LongBreakDuration: viper.GetDuration("timer.long-break"),
-	}, nil
+	cfg := Config{
+		Workflow: workflow,
+	}
+	get(v, &cfg)
+	return cfg, nil
 }
@@ -0,0 +1,79 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Default(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := config.Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, config.Default(), cfg)
+	data, err := os.ReadFile(filepath.Join(dir, "config.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "pomodoro: 25m\n")
+	assert.Contains(t, string(data), "long-break: 15m\n")
+
+	// loading the generated file again gives the same result
+	cfg, err = config.Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, config.Default(), cfg)
+}
+
+func TestLoad_Workflow(t *testing.T) {
+	tests := map[string]struct {
+		columns string
+		want    []pomo.Column
+		wantErr string
+	}{
+		"custom columns": {
+			columns: `
+        - {status: backlog, name: Backlog}
+        - {status: todo}
+        - {status: doing, name: Doing, worked-on: true}
+        - {status: done, name: Done, done: true, worked-on: true}`,
+			want: []pomo.Column{
+				{Status: "backlog", Name: "Backlog"},
+				{Status: pomo.Todo, Name: "todo"},
+				{Status: pomo.Doing, Name: "Doing", WorkedOn: true},
+				{Status: pomo.Done, Name: "Done", Done: true, WorkedOn: true},
+			},
+		},
+		"duplicate status": {
+			columns: `
+        - {status: todo}
+        - {status: todo, name: Next}
+        - {status: done, done: true}`,
+			wantErr: "loading workflow: duplicate status: todo",
+		},
+		"no done column": {
+			columns: `
+        - {status: todo}
+        - {status: doing}`,
+			wantErr: "loading workflow: expected one done column, got 0",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("workflow:\n    columns:"+tc.columns+"\n"), 0o600)
+			require.NoError(t, err)
+
+			cfg, err := config.Load(dir)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, cfg.Workflow.Columns)
+		})
+	}
+}
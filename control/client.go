@@ -0,0 +1,40 @@
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Do sends a request to the pomo app listening on the socket at the given path,
+// and returns its response. A response reporting a failure is returned as an
+// error.
+func Do(path string, req Request) (Response, error) {
+	var resp Response
+
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return resp, fmt.Errorf("connecting to pomo (is it running?): %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	_ = conn.SetDeadline(time.Now().Add(2 * timeout))
+
+	err = json.NewEncoder(conn).Encode(req)
+	if err != nil {
+		return resp, fmt.Errorf("sending request: %w", err)
+	}
+
+	err = json.NewDecoder(conn).Decode(&resp)
+	if err != nil {
+		return resp, fmt.Errorf("reading response: %w", err)
+	}
+
+	if !resp.OK {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
@@ -0,0 +1,74 @@
+package control_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/qualidafial/pomo/control"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControl(t *testing.T) {
+	// unix socket paths are limited in length, so avoid the long t.TempDir()
+	dir, err := os.MkdirTemp("", "pomo")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	socket := filepath.Join(dir, "pomo.sock")
+
+	srv, err := control.Listen(socket)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, srv.Close())
+	}()
+
+	go func() {
+		_ = srv.Serve(func(msg tea.Msg) {
+			req := msg.(control.RequestMsg)
+			if !req.Accept() {
+				return
+			}
+			switch req.Request.Verb {
+			case control.VerbStatus:
+				req.Reply(control.Response{
+					OK: true,
+					Status: &control.Status{
+						State: "idle",
+						Pomo:  3,
+					},
+				})
+			default:
+				req.Reply(control.Response{
+					Error: "cannot " + req.Request.Verb,
+				})
+			}
+		})
+	}()
+
+	resp, err := control.Do(socket, control.Request{Verb: control.VerbStatus})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Status)
+	assert.Equal(t, "idle", resp.Status.State)
+	assert.Equal(t, 3, resp.Status.Pomo)
+
+	_, err = control.Do(socket, control.Request{Verb: control.VerbStart})
+	assert.EqualError(t, err, "cannot start")
+
+	_, err = control.Listen(socket)
+	assert.Error(t, err, "socket already in use")
+}
+
+func TestRequestMsg_Expire(t *testing.T) {
+	msg, reply := control.NewRequestMsg(control.Request{Verb: control.VerbStart})
+	assert.True(t, msg.Expire())
+	assert.False(t, msg.Accept(), "an expired request is not applied")
+	assert.Empty(t, reply)
+
+	msg, _ = control.NewRequestMsg(control.Request{Verb: control.VerbStart})
+	assert.True(t, msg.Accept())
+	assert.False(t, msg.Expire(), "an accepted request is waited for")
+}
@@ -0,0 +1,52 @@
+// Package control implements a local socket through which other programs can
+// query and drive a running pomo app.
+//
+// Each connection carries a single exchange: the client writes one JSON
+// encoded Request, and the server writes back one JSON encoded Response.
+package control
+
+import (
+	"time"
+)
+
+const (
+	VerbStatus   = "status"
+	VerbStart    = "start"
+	VerbCancel   = "cancel"
+	VerbComplete = "complete"
+	VerbAddTask  = "add-task"
+)
+
+type Request struct {
+	Verb string `json:"verb"`
+
+	// Reason is the optional reason for cancelling a pomodoro.
+	Reason string `json:"reason,omitempty"`
+
+	// Name and Status describe the task to add with VerbAddTask. Status
+	// defaults to todo.
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+type Response struct {
+	OK     bool    `json:"ok"`
+	Error  string  `json:"error,omitempty"`
+	Status *Status `json:"status,omitempty"`
+}
+
+// Status describes the state of the pomodoro timer.
+type Status struct {
+	// State is one of idle, active, ended, break, long-break or break-ended.
+	State  string `json:"state"`
+	Paused bool   `json:"paused,omitempty"`
+	// Pomo is the number of the current or next pomodoro today.
+	Pomo int `json:"pomo"`
+	// Completed is the number of pomodoros completed today.
+	Completed int `json:"completed"`
+	DailyGoal int `json:"dailyGoal,omitempty"`
+	// End is when the running pomodoro or break timer ends, if one is running.
+	End *time.Time `json:"end,omitempty"`
+	// Remaining is the time left on the running or paused timer, in seconds.
+	Remaining int `json:"remaining,omitempty"`
+}
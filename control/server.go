@@ -0,0 +1,149 @@
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+const timeout = 5 * time.Second
+
+// RequestMsg delivers a control request to the app. The app must call Accept
+// before applying the request, and Reply exactly once if it was accepted.
+type RequestMsg struct {
+	Request Request
+
+	reply chan<- Response
+	// claimed is set once the request is either accepted by the app or
+	// expired by the server, whichever comes first.
+	claimed *atomic.Bool
+}
+
+// NewRequestMsg returns a message delivering the given request, and the channel
+// on which its response will be received.
+func NewRequestMsg(req Request) (RequestMsg, <-chan Response) {
+	reply := make(chan Response, 1)
+	return RequestMsg{
+		Request: req,
+		reply:   reply,
+		claimed: new(atomic.Bool),
+	}, reply
+}
+
+// Accept claims the request for the app. Returns false if the request has
+// expired, in which case the app must ignore it without replying.
+func (m RequestMsg) Accept() bool {
+	return m.claimed.CompareAndSwap(false, true)
+}
+
+// Expire withdraws the request, so the app will ignore it. Returns false if
+// the app has already accepted the request, in which case it will reply.
+func (m RequestMsg) Expire() bool {
+	return m.claimed.CompareAndSwap(false, true)
+}
+
+func (m RequestMsg) Reply(resp Response) {
+	m.reply <- resp
+}
+
+type Server struct {
+	path     string
+	listener net.Listener
+}
+
+// Listen creates the control socket at the given path. A socket left behind by
+// a pomo app that exited uncleanly is replaced, but Listen fails if another
+// pomo app is listening on it.
+func Listen(path string) (*Server, error) {
+	if _, err := os.Stat(path); err == nil {
+		conn, err := net.DialTimeout("unix", path, timeout)
+		if err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("another pomo is already listening on %s", path)
+		}
+		err = os.Remove(path)
+		if err != nil {
+			return nil, fmt.Errorf("removing stale control socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listening on control socket: %w", err)
+	}
+
+	err = os.Chmod(path, 0o600)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("restricting control socket permissions: %w", err), listener.Close())
+	}
+
+	return &Server{
+		path:     path,
+		listener: listener,
+	}, nil
+}
+
+// Serve accepts connections until the server is closed, delivering each
+// request to the app through send.
+func (s *Server) Serve(send func(tea.Msg)) error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("accepting control connection: %w", err)
+		}
+		go s.handle(conn, send)
+	}
+}
+
+func (s *Server) handle(conn net.Conn, send func(tea.Msg)) {
+	defer func() {
+		_ = conn.Close()
+	}()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	var req Request
+	var resp Response
+	err := json.NewDecoder(conn).Decode(&req)
+	if err != nil {
+		resp.Error = fmt.Sprintf("decoding request: %v", err)
+	} else {
+		msg, reply := NewRequestMsg(req)
+		// send blocks while the app is busy, so it counts against the timeout
+		go send(msg)
+		select {
+		case resp = <-reply:
+		case <-time.After(timeout):
+			if msg.Expire() {
+				resp.Error = "timed out waiting for pomo"
+			} else {
+				// the app is applying the request, so it must not be
+				// reported as failed
+				resp = <-reply
+			}
+		}
+	}
+
+	err = json.NewEncoder(conn).Encode(resp)
+	if err != nil {
+		log.Error("writing control response", "verb", req.Verb, "err", err)
+	}
+}
+
+// Close stops listening and removes the control socket.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	if errors.Is(err, net.ErrClosed) {
+		err = nil
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+package pomo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ChangeKind is the kind of change made to a task between two boards.
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Removed
+	Modified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", k)
+	}
+}
+
+// Mark returns the diff mark for the kind of change.
+func (k ChangeKind) Mark() string {
+	switch k {
+	case Added:
+		return "+"
+	case Removed:
+		return "-"
+	case Modified:
+		return "~"
+	default:
+		return "?"
+	}
+}
+
+// TaskChange is a change made to a task between two boards.
+type TaskChange struct {
+	Kind ChangeKind
+	// Task is the task after the change, or before it if removed.
+	Task Task
+	// Fields describes the changes to a modified task, e.g. "status: doing →
+	// done".
+	Fields []string
+}
+
+// DiffTasks returns the changes that turn the tasks in from into the tasks in
+// to, matching tasks by ID. Tasks saved before task IDs were introduced have no
+// ID, and are matched by name instead, in order. Added and modified tasks are
+// listed in the order of to, followed by removed tasks in the order of from.
+// Update times are ignored.
+func DiffTasks(from, to []Task) []TaskChange {
+	matches := matchTasks(from, to)
+
+	var changes []TaskChange
+	matched := make([]bool, len(from))
+	for j, task := range to {
+		i := matches[j]
+		if i < 0 {
+			changes = append(changes, TaskChange{
+				Kind: Added,
+				Task: task,
+			})
+			continue
+		}
+		matched[i] = true
+		if fields := diffTask(from[i], task); len(fields) > 0 {
+			changes = append(changes, TaskChange{
+				Kind:   Modified,
+				Task:   task,
+				Fields: fields,
+			})
+		}
+	}
+
+	for i, task := range from {
+		if !matched[i] {
+			changes = append(changes, TaskChange{
+				Kind: Removed,
+				Task: task,
+			})
+		}
+	}
+	return changes
+}
+
+// matchTasks returns, for each task in to, the index of the same task in from,
+// or -1 if there is none. Tasks are matched by ID, and tasks without an ID by
+// name, each to the first task not yet matched.
+func matchTasks(from, to []Task) []int {
+	byID := make(map[string]int, len(from))
+	for i, task := range from {
+		if task.ID != "" {
+			byID[task.ID] = i
+		}
+	}
+
+	matches := make([]int, len(to))
+	matched := make([]bool, len(from))
+	for j, task := range to {
+		matches[j] = -1
+		if i, ok := byID[task.ID]; ok && task.ID != "" {
+			matches[j] = i
+			matched[i] = true
+		}
+	}
+	for j, task := range to {
+		if matches[j] >= 0 {
+			continue
+		}
+		for i, old := range from {
+			if !matched[i] && (task.ID == "" || old.ID == "") && old.Name == task.Name {
+				matches[j] = i
+				matched[i] = true
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func diffTask(from, to Task) []string {
+	var fields []string
+	if from.Status != to.Status {
+		fields = append(fields, fmt.Sprintf("status: %s → %s", from.Status, to.Status))
+	}
+	if from.Name != to.Name {
+		fields = append(fields, fmt.Sprintf("name: %q → %q", from.Name, to.Name))
+	}
+	if from.Notes != to.Notes {
+		fields = append(fields, "notes")
+	}
+	if !slices.Equal(from.Tags, to.Tags) {
+		fields = append(fields, fmt.Sprintf("tags: [%s] → [%s]", strings.Join(from.Tags, " "), strings.Join(to.Tags, " ")))
+	}
+	if from.Estimate != to.Estimate {
+		fields = append(fields, fmt.Sprintf("estimate: %d → %d", from.Estimate, to.Estimate))
+	}
+	if !slices.Equal(from.Checklist, to.Checklist) {
+		fromDone, fromTotal := from.Progress()
+		toDone, toTotal := to.Progress()
+		fields = append(fields, fmt.Sprintf("checklist: %d/%d → %d/%d", fromDone, fromTotal, toDone, toTotal))
+	}
+	return fields
+}
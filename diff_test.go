@@ -0,0 +1,54 @@
+package pomo_test
+
+import (
+	"testing"
+
+	"github.com/qualidafial/pomo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTasks(t *testing.T) {
+	fence := pomo.Task{ID: "a", Status: pomo.Todo, Name: "Paint the fence"}
+	car := pomo.Task{ID: "b", Status: pomo.Doing, Name: "Wax the car"}
+	floor := pomo.Task{ID: "c", Status: pomo.Todo, Name: "Sand the floor"}
+
+	doneFence := fence
+	doneFence.Status = pomo.Done
+
+	assert.Equal(t, []pomo.TaskChange{
+		{Kind: pomo.Modified, Task: doneFence, Fields: []string{"status: todo → done"}},
+		{Kind: pomo.Added, Task: floor},
+		{Kind: pomo.Removed, Task: car},
+	}, pomo.DiffTasks([]pomo.Task{fence, car}, []pomo.Task{doneFence, floor}))
+}
+
+func TestDiffTasks_WithoutIDs(t *testing.T) {
+	// snapshots taken before task IDs were introduced
+	fence := pomo.Task{Status: pomo.Todo, Name: "Paint the fence"}
+	car := pomo.Task{Status: pomo.Doing, Name: "Wax the car"}
+	floor := pomo.Task{Status: pomo.Todo, Name: "Sand the floor"}
+
+	doneCar := car
+	doneCar.Status = pomo.Done
+
+	assert.Equal(t, []pomo.TaskChange{
+		{Kind: pomo.Modified, Task: doneCar, Fields: []string{"status: doing → done"}},
+		{Kind: pomo.Added, Task: floor},
+		{Kind: pomo.Removed, Task: fence},
+	}, pomo.DiffTasks([]pomo.Task{fence, car}, []pomo.Task{doneCar, floor}))
+
+	// the current board has IDs, the snapshot doesn't
+	board := []pomo.Task{
+		{ID: "a", Status: pomo.Todo, Name: "Paint the fence"},
+		{ID: "b", Status: pomo.Done, Name: "Wax the car"},
+	}
+	assert.Equal(t, []pomo.TaskChange{
+		{Kind: pomo.Modified, Task: car, Fields: []string{"status: done → doing"}},
+	}, pomo.DiffTasks(board, []pomo.Task{fence, car}))
+
+	// tasks with the same name are matched in order
+	assert.Empty(t, pomo.DiffTasks([]pomo.Task{fence, fence}, []pomo.Task{fence, fence}))
+	assert.Equal(t, []pomo.TaskChange{
+		{Kind: pomo.Removed, Task: fence},
+	}, pomo.DiffTasks([]pomo.Task{fence, fence}, []pomo.Task{fence}))
+}
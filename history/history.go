@@ -0,0 +1,174 @@
+// Package history provides a day-by-day browser of completed pomodoros.
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/message"
+	"github.com/qualidafial/pomo/tasklist"
+)
+
+const (
+	pomoListWidth = 32
+	timeFormat    = "15:04"
+)
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	clock clock.Clock
+
+	width  int
+	height int
+
+	day   time.Time
+	pomos []pomo.Pomo
+	index int
+
+	tasks tasklist.Model
+}
+
+func New(c clock.Clock) Model {
+	return Model{
+		KeyMap: DefaultKeyMap(),
+		Styles: DefaultStyles(),
+
+		clock: c,
+
+		tasks: tasklist.New("Tasks", nil),
+	}
+}
+
+// Open returns a command to load the pomodoros of the given day.
+func (m Model) Open(day time.Time) tea.Cmd {
+	return message.LoadHistory(startOfDay(day))
+}
+
+// Day returns the day currently displayed.
+func (m Model) Day() time.Time {
+	return m.day
+}
+
+// SetPomos sets the day displayed and the pomodoros completed on it.
+func (m *Model) SetPomos(day time.Time, pomos []pomo.Pomo) tea.Cmd {
+	m.day = startOfDay(day)
+	m.pomos = pomos
+	m.index = 0
+	return m.selectPomo(0)
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.Up):
+			cmd = m.selectPomo(m.index - 1)
+		case key.Matches(msg, m.KeyMap.Down):
+			cmd = m.selectPomo(m.index + 1)
+		case key.Matches(msg, m.KeyMap.PrevDay):
+			cmd = m.Open(m.day.AddDate(0, 0, -1))
+		case key.Matches(msg, m.KeyMap.NextDay):
+			cmd = m.Open(m.day.AddDate(0, 0, 1))
+		case key.Matches(msg, m.KeyMap.Today):
+			cmd = m.Open(m.clock.Now())
+		case key.Matches(msg, m.KeyMap.Close):
+			cmd = message.CloseHistory
+		}
+	}
+
+	today := startOfDay(m.clock.Now())
+	m.KeyMap.Up.SetEnabled(m.index > 0)
+	m.KeyMap.Down.SetEnabled(m.index+1 < len(m.pomos))
+	m.KeyMap.NextDay.SetEnabled(m.day.Before(today))
+	m.KeyMap.Today.SetEnabled(!m.day.Equal(today))
+
+	return m, cmd
+}
+
+func (m *Model) selectPomo(index int) tea.Cmd {
+	if index >= len(m.pomos) {
+		index = len(m.pomos) - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	m.index = index
+
+	if len(m.pomos) == 0 {
+		return m.tasks.SetTasks(nil)
+	}
+	return m.tasks.SetTasks(m.pomos[index].Tasks)
+}
+
+func (m *Model) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+func (m Model) View() string {
+	title := m.Styles.Title.Render(fmt.Sprintf("History: %s", m.day.Format("Monday, January 2, 2006")))
+
+	height := max(0, m.height-lipgloss.Height(title))
+	listWidth := min(pomoListWidth, m.width/2)
+
+	m.tasks.SetSize(m.width-listWidth, height)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		lipgloss.JoinHorizontal(lipgloss.Top,
+			m.viewPomos(listWidth, height),
+			m.tasks.View(),
+		),
+	)
+}
+
+func (m Model) viewPomos(width, height int) string {
+	frame := m.Styles.Frame.
+		Width(max(0, width-m.Styles.Frame.GetHorizontalBorderSize())).
+		Height(max(0, height-m.Styles.Frame.GetVerticalBorderSize()))
+
+	if len(m.pomos) == 0 {
+		return frame.Render(m.Styles.Empty.Render("No pomodoros"))
+	}
+
+	var lines []string
+	var completed int
+	for i, p := range m.pomos {
+		// only completed pomodoros are numbered
+		number := "  "
+		summary := pomo.Plural(len(p.Tasks), "task")
+		if p.Completed() {
+			completed++
+			number = fmt.Sprintf("%2d", completed)
+		} else {
+			summary = "✗ " + p.Outcome.String()
+		}
+
+		line := fmt.Sprintf("%s  %s–%s  %s", number, p.Start.Local().Format(timeFormat), p.End.Local().Format(timeFormat), summary)
+		if i == m.index && p.Reason != "" {
+			line += "\n" + strings.Repeat(" ", 4) + p.Reason
+		}
+		if i == m.index {
+			line = m.Styles.Selected.Render(line)
+		} else {
+			line = m.Styles.Pomo.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return frame.Render(strings.Join(lines, "\n"))
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
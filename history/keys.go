@@ -0,0 +1,71 @@
+package history
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type KeyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	PrevDay key.Binding
+	NextDay key.Binding
+	Today   key.Binding
+	Close   key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "previous pomo"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "next pomo"),
+		),
+		PrevDay: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "previous day"),
+		),
+		NextDay: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "next day"),
+		),
+		Today: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "today"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "v"),
+			key.WithHelp("esc", "close history"),
+		),
+	}
+}
+
+func (m KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			m.Up,
+			m.Down,
+		},
+		{
+			m.PrevDay,
+			m.NextDay,
+			m.Today,
+		},
+		{
+			m.Close,
+		},
+	}
+}
+
+func (m KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.Up,
+		m.Down,
+		m.PrevDay,
+		m.NextDay,
+		m.Today,
+		m.Close,
+	}
+}
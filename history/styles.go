@@ -0,0 +1,37 @@
+package history
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/qualidafial/pomo/color"
+)
+
+type Styles struct {
+	Title    lipgloss.Style
+	Frame    lipgloss.Style
+	Pomo     lipgloss.Style
+	Selected lipgloss.Style
+	Empty    lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Padding(0, 1).
+			Background(lipgloss.Color("62")).
+			Foreground(lipgloss.Color("230")),
+		Frame: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")),
+		Pomo: lipgloss.NewStyle().
+			Padding(0, 0, 0, 2),
+		Selected: lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("#AD58B4")).
+			Foreground(lipgloss.Color("#EE6FF8")).
+			Padding(0, 0, 0, 1),
+		Empty: lipgloss.NewStyle().
+			Padding(0, 0, 0, 2).
+			Foreground(color.Gray),
+	}
+}
@@ -1,12 +1,15 @@
 package kanban
 
 import (
-	"time"
+	"reflect"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
 	"github.com/qualidafial/pomo/message"
 	"github.com/qualidafial/pomo/tasklist"
 )
@@ -17,46 +20,59 @@ const (
 
 type Model struct {
 	KeyMap KeyMap
+	Styles Styles
 
 	width  int
 	height int
 
-	status    pomo.Status
+	// clock stamps tasks moved between columns
+	clock clock.Clock
+
+	workflow  pomo.Workflow
+	column    int
 	taskLists []tasklist.Model
+
+	// filter is a search term applied to all columns at once
+	filter    textinput.Model
+	filtering bool
+
+	undo []snapshot
+	redo []snapshot
 }
 
-func New(tasks []pomo.Task) Model {
-	var todos, doing, done []pomo.Task
-	for _, t := range tasks {
-		switch t.Status {
-		case pomo.Todo:
-			todos = append(todos, t)
-		case pomo.Doing:
-			doing = append(doing, t)
-		case pomo.Done:
-			done = append(done, t)
-		}
-	}
+// New creates a board with a column for each column of the workflow. The clock
+// is used to record when a task last moved.
+func New(workflow pomo.Workflow, tasks []pomo.Task, c clock.Clock) Model {
+	filter := textinput.New()
+	filter.Prompt = "/"
+	filter.Placeholder = "filter by name, notes or tags"
 
 	m := Model{
 		KeyMap: DefaultKeyMap(),
+		Styles: DefaultStyles(),
 
-		width:  0,
-		height: 0,
-		status: pomo.Todo,
+		width:    0,
+		height:   0,
+		clock:    c,
+		workflow: workflow,
+		column:   0,
 
-		taskLists: []tasklist.Model{
-			tasklist.New("To Do", todos),
-			tasklist.New("Doing", doing),
-			tasklist.New("Done", done),
-		},
+		filter: filter,
 	}
+	for _, col := range workflow.Columns {
+		taskList := tasklist.New(col.Name, nil)
+		taskList.SetStatus(col.Status)
+		m.taskLists = append(m.taskLists, taskList)
+	}
+	m.SetTasks(tasks)
+
+	m.updateHistoryKeys()
 
-	for status := range m.taskLists {
-		if status == int(m.status) {
-			m.taskLists[status].Focus(0)
+	for column := range m.taskLists {
+		if column == m.column {
+			m.taskLists[column].Focus(0)
 		} else {
-			m.taskLists[status].Blur()
+			m.taskLists[column].Blur()
 		}
 	}
 
@@ -69,6 +85,14 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
+		case m.filtering:
+			cmd = m.updateFilter(msg)
+		case key.Matches(msg, m.KeyMap.Filter):
+			m.filtering = true
+			cmd = m.filter.Focus()
+		case key.Matches(msg, m.KeyMap.ClearFilter):
+			m.ClearFilter()
+
 		case key.Matches(msg, m.KeyMap.Up):
 			m.Up()
 		case key.Matches(msg, m.KeyMap.Down):
@@ -86,22 +110,36 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			cmd = m.MoveLeft()
 		case key.Matches(msg, m.KeyMap.MoveRight):
 			cmd = m.MoveRight()
+
+		case key.Matches(msg, m.KeyMap.Undo):
+			cmd = m.Undo()
+		case key.Matches(msg, m.KeyMap.Redo):
+			cmd = m.Redo()
 		default:
-			m.taskLists[m.status], cmd = m.taskLists[m.status].Update(msg)
+			m.taskLists[m.column], cmd = m.taskLists[m.column].Update(msg)
 		}
 	default:
-		m.taskLists[m.status], cmd = m.taskLists[m.status].Update(msg)
+		var filterCmd tea.Cmd
+		if m.filtering {
+			m.filter, filterCmd = m.filter.Update(msg)
+		}
+		m.taskLists[m.column], cmd = m.taskLists[m.column].Update(msg)
+		cmd = tea.Batch(cmd, filterCmd)
 	}
 
-	taskList := m.taskLists[m.status]
-	tasks := taskList.Tasks()
+	taskList := m.taskLists[m.column]
+	count := taskList.Count()
 	index := taskList.Index()
-	selection := index >= 0 && index < len(tasks)
+	selection := index >= 0 && index < count
+
+	m.KeyMap.Filter.SetEnabled(!m.filtering)
+	m.KeyMap.ClearFilter.SetEnabled(m.filtering || m.filter.Value() != "")
+	m.KeyMap.AcceptFilter.SetEnabled(m.filtering)
 
 	m.KeyMap.Up.SetEnabled(index > 0)
-	m.KeyMap.Down.SetEnabled(index+1 < len(tasks))
-	m.KeyMap.Left.SetEnabled(m.status > pomo.Todo)
-	m.KeyMap.Right.SetEnabled(m.status < pomo.Done)
+	m.KeyMap.Down.SetEnabled(index+1 < count)
+	m.KeyMap.Left.SetEnabled(m.column > 0)
+	m.KeyMap.Right.SetEnabled(m.column+1 < len(m.taskLists))
 
 	m.KeyMap.Move.SetEnabled(selection)
 	m.KeyMap.MoveUp.SetEnabled(selection && m.KeyMap.Up.Enabled())
@@ -112,11 +150,69 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateFilter handles keys while the filter is being typed.
+func (m *Model) updateFilter(msg tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+	switch {
+	case key.Matches(msg, m.KeyMap.AcceptFilter):
+		m.filtering = false
+		m.filter.Blur()
+		if m.filter.Value() == "" {
+			m.ClearFilter()
+		}
+	case key.Matches(msg, m.KeyMap.ClearFilter):
+		m.ClearFilter()
+	default:
+		m.filter, cmd = m.filter.Update(msg)
+		m.applyFilter()
+	}
+	return cmd
+}
+
+// Filtering returns whether the filter is being typed, in which case all keys
+// are handled by the board.
+func (m Model) Filtering() bool {
+	return m.filtering
+}
+
+// Filter returns the search term the board is filtered by.
+func (m Model) Filter() string {
+	return m.filter.Value()
+}
+
+// SetFilter shows only the tasks in each column that fuzzy match the given
+// term by name, notes or tags.
+func (m *Model) SetFilter(term string) {
+	m.filter.SetValue(term)
+	m.applyFilter()
+}
+
+// ClearFilter stops filtering and shows all tasks.
+func (m *Model) ClearFilter() {
+	m.filtering = false
+	m.filter.Blur()
+	m.SetFilter("")
+}
+
+func (m *Model) applyFilter() {
+	term := strings.TrimSpace(m.filter.Value())
+	for i := range m.taskLists {
+		m.taskLists[i].SetFilter(term)
+	}
+}
+
 func (m Model) View() string {
-	visibleColumns := min(3, max(1, m.width/minColumnWidth))
+	height := m.height
+	var filterBar string
+	if m.filtering || m.filter.Value() != "" {
+		filterBar = m.Styles.Filter.Width(m.width).Render(m.filter.View())
+		height = max(0, height-lipgloss.Height(filterBar))
+	}
+
+	visibleColumns := min(len(m.taskLists), max(1, m.width/minColumnWidth))
 
-	firstColumn := max(pomo.Todo, m.status-pomo.Status(visibleColumns-1))
-	lastColumn := min(pomo.Done, firstColumn+pomo.Status(visibleColumns-1))
+	firstColumn := max(0, m.column-(visibleColumns-1))
+	lastColumn := min(len(m.taskLists)-1, firstColumn+visibleColumns-1)
 
 	var columns []string
 	remainingWidth := m.width
@@ -125,91 +221,128 @@ func (m Model) View() string {
 		remainingWidth -= columnWidth
 		visibleColumns--
 		col := m.taskLists[colIndex]
-		col.SetSize(columnWidth, m.height)
+		col.SetSize(columnWidth, height)
 		columns = append(columns, col.View())
 	}
-	return lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+	board := lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+	if filterBar == "" {
+		return board
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, filterBar, board)
 }
 
+// Status returns the status of the selected column.
 func (m Model) Status() pomo.Status {
-	return m.status
+	return m.workflow.Columns[m.column].Status
 }
 
+// SetStatus selects the column with the given status.
 func (m *Model) SetStatus(status pomo.Status) {
-	if status < pomo.Todo || status > pomo.Done {
+	column := m.workflow.Index(status)
+	if column < 0 {
+		return
+	}
+	m.setColumn(column)
+}
+
+func (m *Model) setColumn(column int) {
+	if column < 0 || column >= len(m.taskLists) {
 		return
 	}
-	i := m.taskLists[m.status].Index()
-	m.taskLists[m.status].Blur()
-	m.status = status
-	m.taskLists[m.status].Focus(i)
+	i := m.taskLists[m.column].Index()
+	m.taskLists[m.column].Blur()
+	m.column = column
+	m.taskLists[m.column].Focus(i)
 }
 
 func (m *Model) Up() {
-	m.taskLists[m.status].Up()
+	m.taskLists[m.column].Up()
 }
 
 func (m *Model) Down() {
-	m.taskLists[m.status].Down()
+	m.taskLists[m.column].Down()
 }
 
 func (m *Model) Left() {
-	if m.status > pomo.Todo {
-		m.SetStatus(m.status - 1)
-	}
+	m.setColumn(m.column - 1)
 }
 
 func (m *Model) Right() {
-	if m.status < pomo.Done {
-		m.SetStatus(m.status + 1)
-	}
+	m.setColumn(m.column + 1)
 }
 
 func (m *Model) MoveUp() tea.Cmd {
-	return tea.Sequence(m.taskLists[m.status].MoveUp(), m.tasksModified())
+	if m.taskLists[m.column].Index() <= 0 {
+		return nil
+	}
+	m.checkpoint()
+	return tea.Sequence(m.taskLists[m.column].MoveUp(), m.tasksModified())
 }
 
 func (m *Model) MoveDown() tea.Cmd {
-	return tea.Sequence(m.taskLists[m.status].MoveDown(), m.tasksModified())
+	list := m.taskLists[m.column]
+	if list.Index() < 0 || list.Index()+1 >= list.Count() {
+		return nil
+	}
+	m.checkpoint()
+	return tea.Sequence(m.taskLists[m.column].MoveDown(), m.tasksModified())
 }
 
 func (m *Model) MoveLeft() tea.Cmd {
-	var cmd tea.Cmd
-
-	task, ok := m.taskLists[m.status].Remove()
-	if ok {
-		m.Left()
-		task.Status = m.status
-		task.UpdatedAt = time.Now()
-		cmd = m.taskLists[m.status].InsertSelect(0, task)
+	if m.column == 0 {
+		return nil
 	}
+	if _, ok := m.Task(); !ok {
+		return nil
+	}
+
+	m.checkpoint()
+	task, _ := m.taskLists[m.column].Remove()
+	m.Left()
+	task.Status = m.Status()
+	task.UpdatedAt = m.clock.Now()
+	cmd := m.taskLists[m.column].InsertSelect(0, task)
 
 	return tea.Sequence(cmd, m.tasksModified())
 }
 
 func (m *Model) MoveRight() tea.Cmd {
-	var cmd tea.Cmd
-
-	task, ok := m.taskLists[m.status].Remove()
-	if ok {
-		m.Right()
-		task.Status = m.status
-		task.UpdatedAt = time.Now()
-		cmd = m.taskLists[m.status].AppendSelect(task)
+	if m.column+1 >= len(m.taskLists) {
+		return nil
 	}
+	if _, ok := m.Task(); !ok {
+		return nil
+	}
+
+	m.checkpoint()
+	task, _ := m.taskLists[m.column].Remove()
+	m.Right()
+	task.Status = m.Status()
+	task.UpdatedAt = m.clock.Now()
+	cmd := m.taskLists[m.column].AppendSelect(task)
 
 	return tea.Sequence(cmd, m.tasksModified())
 }
 
+// AppendSelect adds the task to the end of the column for its status and
+// selects it. The filter is cleared if it would hide the task.
 func (m *Model) AppendSelect(task pomo.Task) tea.Cmd {
+	m.checkpoint()
 	m.SetStatus(task.Status)
-	task.Status = m.status
-	cmd := m.taskLists[m.status].AppendSelect(task)
+	task.Status = m.Status()
+	if !m.taskLists[m.column].Matches(task) {
+		m.ClearFilter()
+	}
+	cmd := m.taskLists[m.column].AppendSelect(task)
 	return tea.Sequence(cmd, m.tasksModified())
 }
 
 func (m *Model) Remove() tea.Cmd {
-	m.taskLists[m.status].Remove()
+	if _, ok := m.Task(); !ok {
+		return nil
+	}
+	m.checkpoint()
+	m.taskLists[m.column].Remove()
 	return m.tasksModified()
 }
 
@@ -221,27 +354,88 @@ func (m Model) Tasks() []pomo.Task {
 	return tasks
 }
 
+// SetTasks places each task in the column for its status. Tasks with a status
+// that is not in the workflow, e.g. after a column is removed from the config,
+// are shown in the first column with their status, which they keep until they
+// are moved. Tasks with no status are given the status of the first column.
 func (m Model) SetTasks(tasks []pomo.Task) tea.Cmd {
-	tasksByStatus := map[pomo.Status][]pomo.Task{}
+	byColumn := make([][]pomo.Task, len(m.taskLists))
+	for _, task := range tasks {
+		if task.Status == "" {
+			task.Status = m.workflow.First()
+		}
+		column := max(0, m.workflow.Index(task.Status))
+		byColumn[column] = append(byColumn[column], task)
+	}
+	var cmds []tea.Cmd
+	for column, tasks := range byColumn {
+		cmds = append(cmds, m.taskLists[column].SetTasks(tasks))
+	}
+	return tea.Batch(cmds...)
+}
+
+// ReplaceTasks replaces all tasks on the board with the given tasks, as a
+// single change that can be undone.
+func (m *Model) ReplaceTasks(tasks []pomo.Task) tea.Cmd {
+	m.checkpoint()
+	return tea.Sequence(m.SetTasks(tasks), m.tasksModified())
+}
+
+// UpdateTasks replaces the tasks on the board with the given tasks of the same
+// ID, keeping their position, as a single change that can be undone. Tasks
+// not on the board are ignored.
+func (m *Model) UpdateTasks(tasks []pomo.Task) tea.Cmd {
+	byID := make(map[string]pomo.Task, len(tasks))
 	for _, task := range tasks {
-		tasksByStatus[task.Status] = append(tasksByStatus[task.Status], task)
+		byID[task.ID] = task
 	}
+
+	changed := make([][]pomo.Task, len(m.taskLists))
+	var modified bool
+	for column, taskList := range m.taskLists {
+		changed[column] = taskList.Tasks()
+		for i, task := range changed[column] {
+			updated, ok := byID[task.ID]
+			if !ok || reflect.DeepEqual(task, updated) {
+				continue
+			}
+			updated.Status = task.Status
+			changed[column][i] = updated
+			modified = true
+		}
+	}
+	if !modified {
+		return nil
+	}
+
+	m.checkpoint()
+	var cmds []tea.Cmd
+	for column, tasks := range changed {
+		cmds = append(cmds, m.taskLists[column].SetTasks(tasks))
+	}
+	return tea.Sequence(tea.Batch(cmds...), m.tasksModified())
+}
+
+// SetActuals sets the number of completed pomodoros each task was worked on,
+// by task ID, shown against the task estimates.
+func (m Model) SetActuals(actuals map[string]int) tea.Cmd {
 	var cmds []tea.Cmd
-	for status := pomo.Todo; status <= pomo.Done; status++ {
-		cmds = append(cmds, m.taskLists[status].SetTasks(tasksByStatus[status]))
+	for column := range m.taskLists {
+		cmds = append(cmds, m.taskLists[column].SetActuals(actuals))
 	}
 	return tea.Batch(cmds...)
 }
 
 // Task returns the currently selected task
 func (m Model) Task() (pomo.Task, bool) {
-	return m.taskLists[m.status].Selection()
+	return m.taskLists[m.column].Selection()
 }
 
 func (m *Model) SetTask(task pomo.Task) tea.Cmd {
-	index := m.taskLists[m.status].Index()
+	m.checkpoint()
+	index := m.taskLists[m.column].Index()
 	return tea.Sequence(
-		m.taskLists[m.status].SetTask(index, task),
+		m.taskLists[m.column].SetTask(index, task),
 		m.tasksModified(),
 	)
 }
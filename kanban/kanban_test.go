@@ -0,0 +1,188 @@
+package kanban_test
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/kanban"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+func names(tasks []pomo.Task) []string {
+	var result []string
+	for _, task := range tasks {
+		result = append(result, task.Name)
+	}
+	return result
+}
+
+func TestUndoRedo(t *testing.T) {
+	m := kanban.New(pomo.DefaultWorkflow(), []pomo.Task{
+		{ID: "a", Status: pomo.Todo, Name: "Paint the fence"},
+		{ID: "b", Status: pomo.Todo, Name: "Wax the car"},
+		{ID: "c", Status: pomo.Doing, Name: "Sand the floor"},
+	}, clock.NewFake(now))
+	assert.False(t, m.KeyMap.Undo.Enabled())
+
+	m.MoveRight()
+	m.Remove()
+	assert.Equal(t, []string{"Wax the car", "Sand the floor"}, names(m.Tasks()))
+	assert.True(t, m.KeyMap.Undo.Enabled())
+	assert.False(t, m.KeyMap.Redo.Enabled())
+
+	// undo the delete
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	assert.Equal(t, []string{"Wax the car", "Sand the floor", "Paint the fence"}, names(m.Tasks()))
+	task, ok := m.Task()
+	assert.True(t, ok)
+	assert.Equal(t, "Paint the fence", task.Name)
+	assert.Equal(t, pomo.Doing, task.Status)
+
+	// undo the move
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	assert.Equal(t, []string{"Paint the fence", "Wax the car", "Sand the floor"}, names(m.Tasks()))
+	assert.False(t, m.KeyMap.Undo.Enabled())
+	assert.True(t, m.KeyMap.Redo.Enabled())
+
+	// redo the move
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	assert.Equal(t, []string{"Wax the car", "Sand the floor", "Paint the fence"}, names(m.Tasks()))
+
+	// a new edit discards what was left to redo
+	m.SetTask(pomo.Task{ID: "a", Status: pomo.Doing, Name: "Paint the gate"})
+	assert.False(t, m.KeyMap.Redo.Enabled())
+	m.Undo()
+	assert.Equal(t, []string{"Wax the car", "Sand the floor", "Paint the fence"}, names(m.Tasks()))
+}
+
+func TestUndo_IgnoresNoOps(t *testing.T) {
+	m := kanban.New(pomo.DefaultWorkflow(), []pomo.Task{
+		{ID: "a", Status: pomo.Todo, Name: "Paint the fence"},
+		{ID: "b", Status: pomo.Todo, Name: "Wax the car"},
+		{ID: "c", Status: pomo.Done, Name: "Sand the floor"},
+	}, clock.NewFake(now))
+
+	m.MoveDown()
+	m.Undo()
+	assert.True(t, m.KeyMap.Redo.Enabled())
+
+	// the top task in the first column can't move up or left
+	assert.Nil(t, m.MoveUp())
+	assert.Nil(t, m.MoveLeft())
+	assert.True(t, m.KeyMap.Redo.Enabled(), "redo is kept")
+	assert.False(t, m.KeyMap.Undo.Enabled(), "nothing to undo")
+
+	// nor can the only task in the last column move down or right
+	m.Right()
+	m.Right()
+	assert.Nil(t, m.MoveDown())
+	assert.Nil(t, m.MoveRight())
+
+	// there is nothing to remove in an empty column
+	m.Left()
+	assert.Nil(t, m.Remove())
+	assert.False(t, m.KeyMap.Undo.Enabled())
+	assert.True(t, m.KeyMap.Redo.Enabled())
+}
+
+func TestFilter(t *testing.T) {
+	m := kanban.New(pomo.DefaultWorkflow(), []pomo.Task{
+		{ID: "a", Status: pomo.Todo, Name: "Paint the fence", Tags: []string{"chores"}},
+		{ID: "b", Status: pomo.Todo, Name: "Wax the car"},
+		{ID: "c", Status: pomo.Todo, Name: "Walk the dog", Notes: "Around the fence"},
+		{ID: "d", Status: pomo.Doing, Name: "Sand the floor", Tags: []string{"chores"}},
+	}, clock.NewFake(now))
+
+	for _, r := range "/chores" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	assert.True(t, m.Filtering())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, m.Filtering())
+	assert.Equal(t, "chores", m.Filter())
+
+	// only matching tasks can be selected, in every column
+	task, _ := m.Task()
+	assert.Equal(t, "Paint the fence", task.Name)
+	assert.False(t, m.KeyMap.Down.Enabled())
+	m.Right()
+	task, _ = m.Task()
+	assert.Equal(t, "Sand the floor", task.Name)
+	m.Left()
+
+	// moves swap with the neighboring visible task
+	m.SetFilter("fence")
+	m.MoveDown()
+	assert.Equal(t, []string{"Walk the dog", "Wax the car", "Paint the fence", "Sand the floor"}, names(m.Tasks()))
+	task, _ = m.Task()
+	assert.Equal(t, "Paint the fence", task.Name)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, "", m.Filter())
+	m.Up()
+	task, _ = m.Task()
+	assert.Equal(t, "Wax the car", task.Name)
+
+	// a new task matching the filter is added and selected
+	m.SetFilter("chores")
+	m.AppendSelect(pomo.Task{ID: "e", Status: pomo.Todo, Name: "Clean the gutters", Tags: []string{"chores"}})
+	assert.Equal(t, "chores", m.Filter())
+	task, _ = m.Task()
+	assert.Equal(t, "Clean the gutters", task.Name)
+
+	// a new task hidden by the filter clears it, so it can be selected
+	m.AppendSelect(pomo.Task{ID: "f", Status: pomo.Todo, Name: "Mow the lawn"})
+	assert.Equal(t, "", m.Filter())
+	task, _ = m.Task()
+	assert.Equal(t, "Mow the lawn", task.Name)
+}
+
+func TestWorkflow(t *testing.T) {
+	workflow := pomo.Workflow{
+		Columns: []pomo.Column{
+			{Status: "backlog", Name: "Backlog"},
+			{Status: "next", Name: "Next"},
+			{Status: "doing", Name: "Doing", WorkedOn: true},
+			{Status: "review", Name: "Review", WorkedOn: true},
+			{Status: "done", Name: "Done", Done: true, WorkedOn: true},
+		},
+	}
+	c := clock.NewFake(now)
+	m := kanban.New(workflow, []pomo.Task{
+		{ID: "a", Status: "review", Name: "Paint the fence", UpdatedAt: now},
+		{ID: "b", Status: "todo", Name: "Wax the car", UpdatedAt: now},
+	}, c)
+	m.SetSize(200, 20)
+
+	// tasks with a status missing from the workflow are shown in the first
+	// column, and keep their status until moved
+	assert.Equal(t, pomo.Status("backlog"), m.Status())
+	task, ok := m.Task()
+	assert.True(t, ok)
+	assert.Equal(t, pomo.Status("todo"), task.Status)
+	assert.Contains(t, m.View(), "? todo")
+
+	c.Advance(time.Minute)
+	for range 3 {
+		m.MoveRight()
+	}
+	assert.Equal(t, pomo.Status("review"), m.Status())
+	task, _ = m.Task()
+	assert.Equal(t, now.Add(time.Minute), task.UpdatedAt, "moves are stamped with the time")
+	m.Right()
+	m.Right()
+	assert.Equal(t, pomo.Status("done"), m.Status())
+
+	m.SetStatus("review")
+	var statuses []pomo.Status
+	for _, task := range m.Tasks() {
+		statuses = append(statuses, task.Status)
+	}
+	assert.Equal(t, []pomo.Status{"review", "review"}, statuses)
+	assert.Equal(t, []string{"Paint the fence", "Wax the car"}, names(m.Tasks()))
+}
@@ -16,6 +16,13 @@ type KeyMap struct {
 	MoveDown  key.Binding
 	MoveLeft  key.Binding
 	MoveRight key.Binding
+
+	Undo key.Binding
+	Redo key.Binding
+
+	Filter       key.Binding
+	ClearFilter  key.Binding
+	AcceptFilter key.Binding
 }
 
 func DefaultKeyMap() KeyMap {
@@ -61,6 +68,30 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("shift+right", "L"),
 			key.WithHelp("shift+→/l", "move right"),
 		),
+
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo"),
+		),
+		Redo: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "redo"),
+		),
+
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		ClearFilter: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear filter"),
+			key.WithDisabled(),
+		),
+		AcceptFilter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "apply filter"),
+			key.WithDisabled(),
+		),
 	}
 }
 
@@ -78,6 +109,15 @@ func (m KeyMap) FullHelp() [][]key.Binding {
 			m.MoveUp,
 			m.MoveRight,
 		},
+		{
+			m.Undo,
+			m.Redo,
+		},
+		{
+			m.Filter,
+			m.AcceptFilter,
+			m.ClearFilter,
+		},
 	}
 }
 
@@ -85,5 +125,10 @@ func (m KeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{
 		m.Navigate,
 		m.Move,
+		m.Undo,
+		m.Redo,
+		m.Filter,
+		m.AcceptFilter,
+		m.ClearFilter,
 	}
 }
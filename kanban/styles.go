@@ -0,0 +1,16 @@
+package kanban
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Styles struct {
+	Filter lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Filter: lipgloss.NewStyle().
+			Padding(0, 1),
+	}
+}
@@ -0,0 +1,84 @@
+package kanban
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/qualidafial/pomo"
+)
+
+// maxUndo is the number of board edits that can be undone.
+const maxUndo = 100
+
+// snapshot is the state of the board before or after an edit.
+type snapshot struct {
+	tasks  []pomo.Task
+	column int
+	index  int
+}
+
+func (m Model) snapshot() snapshot {
+	return snapshot{
+		tasks:  m.Tasks(),
+		column: m.column,
+		index:  m.taskLists[m.column].Index(),
+	}
+}
+
+// checkpoint records the state of the board before an edit, so the edit can
+// be undone.
+func (m *Model) checkpoint() {
+	m.undo = append(m.undo, m.snapshot())
+	if len(m.undo) > maxUndo {
+		m.undo = slices.Delete(m.undo, 0, len(m.undo)-maxUndo)
+	}
+	m.redo = nil
+	m.updateHistoryKeys()
+}
+
+// Undo reverts the most recent board edit.
+func (m *Model) Undo() tea.Cmd {
+	if len(m.undo) == 0 {
+		return nil
+	}
+	m.redo = append(m.redo, m.snapshot())
+	s := m.undo[len(m.undo)-1]
+	m.undo = m.undo[:len(m.undo)-1]
+	m.updateHistoryKeys()
+	return m.restore(s)
+}
+
+// Redo reapplies the most recently undone board edit.
+func (m *Model) Redo() tea.Cmd {
+	if len(m.redo) == 0 {
+		return nil
+	}
+	m.undo = append(m.undo, m.snapshot())
+	s := m.redo[len(m.redo)-1]
+	m.redo = m.redo[:len(m.redo)-1]
+	m.updateHistoryKeys()
+	return m.restore(s)
+}
+
+// ClearHistory forgets all undo and redo history, e.g. after tasks are
+// removed from the board by completing a pomodoro.
+func (m *Model) ClearHistory() {
+	m.undo = nil
+	m.redo = nil
+	m.updateHistoryKeys()
+}
+
+// updateHistoryKeys enables the undo and redo keys when there is something to
+// undo or redo. Edits can be made outside of Update, so the keys are updated
+// as the history changes.
+func (m *Model) updateHistoryKeys() {
+	m.KeyMap.Undo.SetEnabled(len(m.undo) > 0)
+	m.KeyMap.Redo.SetEnabled(len(m.redo) > 0)
+}
+
+func (m *Model) restore(s snapshot) tea.Cmd {
+	cmd := m.SetTasks(s.tasks)
+	m.setColumn(s.column)
+	m.taskLists[m.column].Select(s.index)
+	return tea.Sequence(cmd, m.tasksModified())
+}
@@ -0,0 +1,32 @@
+package message
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/qualidafial/pomo"
+)
+
+func LoadArchive() tea.Msg {
+	return LoadArchiveMsg{}
+}
+
+// LoadArchiveMsg requests the archived tasks.
+type LoadArchiveMsg struct{}
+
+func CloseArchive() tea.Msg {
+	return CloseArchiveMsg{}
+}
+
+type CloseArchiveMsg struct{}
+
+func RestoreTask(task pomo.ArchivedTask) tea.Cmd {
+	return func() tea.Msg {
+		return RestoreTaskMsg{
+			Task: task,
+		}
+	}
+}
+
+// RestoreTaskMsg requests that an archived task be moved back onto the board.
+type RestoreTaskMsg struct {
+	Task pomo.ArchivedTask
+}
@@ -0,0 +1,26 @@
+package message
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func LoadHistory(day time.Time) tea.Cmd {
+	return func() tea.Msg {
+		return LoadHistoryMsg{
+			Day: day,
+		}
+	}
+}
+
+// LoadHistoryMsg requests the pomodoros completed on the given day.
+type LoadHistoryMsg struct {
+	Day time.Time
+}
+
+func CloseHistory() tea.Msg {
+	return CloseHistoryMsg{}
+}
+
+type CloseHistoryMsg struct{}
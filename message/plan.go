@@ -0,0 +1,54 @@
+package message
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/qualidafial/pomo"
+)
+
+// LoadPlan loads the plan for the day, along with the IDs of the planned tasks
+// that have been archived, opening the planning screen if open is true.
+func LoadPlan(plan pomo.Plan, archived []string, open bool) tea.Cmd {
+	return func() tea.Msg {
+		return LoadPlanMsg{
+			Plan:     plan,
+			Archived: archived,
+			Open:     open,
+		}
+	}
+}
+
+type LoadPlanMsg struct {
+	Plan pomo.Plan
+	// Archived holds the IDs of the planned tasks that have been archived.
+	Archived []string
+	Open     bool
+}
+
+func OpenPlan() tea.Msg {
+	return OpenPlanMsg{}
+}
+
+// OpenPlanMsg requests the planning screen for today.
+type OpenPlanMsg struct{}
+
+// SavePlan saves the plan for the day, along with the estimates of all tasks
+// considered while planning.
+func SavePlan(plan pomo.Plan, tasks []pomo.Task) tea.Cmd {
+	return func() tea.Msg {
+		return SavePlanMsg{
+			Plan:  plan,
+			Tasks: tasks,
+		}
+	}
+}
+
+type SavePlanMsg struct {
+	Plan  pomo.Plan
+	Tasks []pomo.Task
+}
+
+func ClosePlan() tea.Msg {
+	return ClosePlanMsg{}
+}
+
+type ClosePlanMsg struct{}
@@ -0,0 +1,52 @@
+package message
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/qualidafial/pomo"
+)
+
+func LoadSnapshots() tea.Msg {
+	return LoadSnapshotsMsg{}
+}
+
+// LoadSnapshotsMsg requests the times of the snapshots of the current
+// pomodoro.
+type LoadSnapshotsMsg struct{}
+
+func LoadSnapshot(t time.Time) tea.Cmd {
+	return func() tea.Msg {
+		return LoadSnapshotMsg{
+			Time: t,
+		}
+	}
+}
+
+// LoadSnapshotMsg requests the snapshot of the current pomodoro taken at the
+// given time.
+type LoadSnapshotMsg struct {
+	Time time.Time
+}
+
+func RestoreSnapshot(t time.Time, snapshot pomo.Pomo) tea.Cmd {
+	return func() tea.Msg {
+		return RestoreSnapshotMsg{
+			Time:     t,
+			Snapshot: snapshot,
+		}
+	}
+}
+
+// RestoreSnapshotMsg requests that the board be restored to the tasks in the
+// snapshot taken at the given time.
+type RestoreSnapshotMsg struct {
+	Time     time.Time
+	Snapshot pomo.Pomo
+}
+
+func CloseTimeTravel() tea.Msg {
+	return CloseTimeTravelMsg{}
+}
+
+type CloseTimeTravelMsg struct{}
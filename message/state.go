@@ -5,11 +5,15 @@ import (
 	"github.com/qualidafial/pomo"
 )
 
-func LoadState(current pomo.Pomo, previous []pomo.Pomo) tea.Cmd {
+// LoadState loads the current pomodoro and the pomodoros completed earlier in
+// the day. Actuals holds the number of completed pomodoros each task was
+// worked on, by task ID, or nil if unchanged.
+func LoadState(current pomo.Pomo, previous []pomo.Pomo, actuals map[string]int) tea.Cmd {
 	return func() tea.Msg {
 		return LoadStateMsg{
 			Current:  current,
 			Previous: previous,
+			Actuals:  actuals,
 		}
 	}
 }
@@ -17,4 +21,5 @@ func LoadState(current pomo.Pomo, previous []pomo.Pomo) tea.Cmd {
 type LoadStateMsg struct {
 	Current  pomo.Pomo
 	Previous []pomo.Pomo
+	Actuals  map[string]int
 }
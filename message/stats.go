@@ -0,0 +1,18 @@
+package message
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func LoadStats() tea.Msg {
+	return LoadStatsMsg{}
+}
+
+// LoadStatsMsg requests the pomodoro history for the stats view.
+type LoadStatsMsg struct{}
+
+func CloseStats() tea.Msg {
+	return CloseStatsMsg{}
+}
+
+type CloseStatsMsg struct{}
@@ -15,6 +15,8 @@ func NewTask(status pomo.Status) tea.Cmd {
 
 type NewTaskMsg struct {
 	Status pomo.Status
+	// Name, if set, adds the task directly instead of opening the task editor.
+	Name string
 }
 
 func EditTask(task pomo.Task) tea.Cmd {
@@ -0,0 +1,74 @@
+package pomo
+
+import (
+	"time"
+)
+
+// Plan is the set of tasks picked to work on during a day, with the estimates
+// they had when planned.
+type Plan struct {
+	// Day is the start of the planned day, in local time.
+	Day   time.Time
+	Tasks []Task
+}
+
+// Estimate returns the total number of pomodoros planned for the day.
+func (p Plan) Estimate() int {
+	var total int
+	for _, task := range p.Tasks {
+		total += task.Estimate
+	}
+	return total
+}
+
+// Includes returns whether the task with the given ID is part of the plan.
+func (p Plan) Includes(id string) bool {
+	for _, task := range p.Tasks {
+		if task.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Plan) MarshalYAML() (any, error) {
+	tasks := make([]task, len(p.Tasks))
+	for i, t := range p.Tasks {
+		tasks[i] = t.yaml()
+	}
+	return plan{
+		Day:   p.Day.Format(time.DateOnly),
+		Tasks: tasks,
+	}, nil
+}
+
+func (p *Plan) UnmarshalYAML(unmarshal func(any) error) error {
+	var data plan
+	if err := unmarshal(&data); err != nil {
+		return err
+	}
+
+	day, err := time.ParseInLocation(time.DateOnly, data.Day, time.Local)
+	if err != nil {
+		return err
+	}
+
+	tasks := make([]Task, len(data.Tasks))
+	for i, t := range data.Tasks {
+		tasks[i], err = t.parse()
+		if err != nil {
+			return err
+		}
+	}
+
+	*p = Plan{
+		Day:   day,
+		Tasks: tasks,
+	}
+	return nil
+}
+
+type plan struct {
+	Day   string `yaml:"day"`
+	Tasks []task `yaml:"tasks,omitempty"`
+}
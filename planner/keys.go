@@ -0,0 +1,78 @@
+package planner
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type KeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Toggle key.Binding
+	More   key.Binding
+	Less   key.Binding
+	Save   key.Binding
+	Close  key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "previous task"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "next task"),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys(" ", "x"),
+			key.WithHelp("space", "plan/unplan task"),
+		),
+		More: key.NewBinding(
+			key.WithKeys("+", "=", "right", "l"),
+			key.WithHelp("+", "raise estimate"),
+		),
+		Less: key.NewBinding(
+			key.WithKeys("-", "left", "h"),
+			key.WithHelp("-", "lower estimate"),
+		),
+		Save: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "save plan"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "P"),
+			key.WithHelp("esc", "skip planning"),
+		),
+	}
+}
+
+func (m KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			m.Up,
+			m.Down,
+		},
+		{
+			m.Toggle,
+			m.More,
+			m.Less,
+		},
+		{
+			m.Save,
+			m.Close,
+		},
+	}
+}
+
+func (m KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.Up,
+		m.Down,
+		m.Toggle,
+		m.More,
+		m.Less,
+		m.Save,
+		m.Close,
+	}
+}
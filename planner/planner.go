@@ -0,0 +1,242 @@
+// Package planner provides a screen for picking the tasks to work on during
+// the day and estimating them, compared against the daily goal.
+package planner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/message"
+)
+
+const (
+	dayFormat   = "Mon Jan 2"
+	maxEstimate = 99
+)
+
+type entry struct {
+	task    pomo.Task
+	planned bool
+}
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	width  int
+	height int
+
+	day  time.Time
+	goal int
+	// plan is the saved plan, used to mark the planned tasks when the tasks
+	// are set
+	plan pomo.Plan
+
+	entries []entry
+	index   int
+}
+
+func New() Model {
+	return Model{
+		KeyMap: DefaultKeyMap(),
+		Styles: DefaultStyles(),
+	}
+}
+
+// Open returns a command to open the planning screen.
+func (m Model) Open() tea.Cmd {
+	return message.OpenPlan
+}
+
+// SetPlan sets the day being planned, the daily goal in pomodoros, the tasks
+// that may be planned, and the saved plan for the day, if any.
+func (m *Model) SetPlan(day time.Time, goal int, tasks []pomo.Task, plan pomo.Plan) {
+	m.day = day
+	m.goal = goal
+	m.plan = plan
+	m.entries = nil
+	m.index = 0
+	m.SetTasks(tasks)
+}
+
+// SetTasks sets the tasks that may be planned, keeping the choices and
+// estimates already made for tasks with the same ID.
+func (m *Model) SetTasks(tasks []pomo.Task) {
+	previous := make(map[string]entry, len(m.entries))
+	for _, e := range m.entries {
+		previous[e.task.ID] = e
+	}
+
+	m.entries = make([]entry, len(tasks))
+	for i, task := range tasks {
+		e, ok := previous[task.ID]
+		if ok {
+			e.task.Name = task.Name
+			e.task.Status = task.Status
+		} else {
+			e = entry{
+				task:    task,
+				planned: m.plan.Day.Equal(m.day) && m.plan.Includes(task.ID),
+			}
+		}
+		m.entries[i] = e
+	}
+	m.index = max(0, min(m.index, len(m.entries)-1))
+	m.updateKeys()
+}
+
+// Plan returns the plan for the day.
+func (m Model) Plan() pomo.Plan {
+	plan := pomo.Plan{
+		Day: m.day,
+	}
+	for _, e := range m.entries {
+		if e.planned {
+			plan.Tasks = append(plan.Tasks, e.task)
+		}
+	}
+	return plan
+}
+
+// Tasks returns the tasks that may be planned, with their estimates.
+func (m Model) Tasks() []pomo.Task {
+	tasks := make([]pomo.Task, len(m.entries))
+	for i, e := range m.entries {
+		tasks[i] = e.task
+	}
+	return tasks
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.Up):
+			m.index--
+		case key.Matches(msg, m.KeyMap.Down):
+			m.index++
+		case key.Matches(msg, m.KeyMap.Toggle):
+			m.entries[m.index].planned = !m.entries[m.index].planned
+		case key.Matches(msg, m.KeyMap.More):
+			m.setEstimate(m.entries[m.index].task.Estimate + 1)
+		case key.Matches(msg, m.KeyMap.Less):
+			m.setEstimate(m.entries[m.index].task.Estimate - 1)
+		case key.Matches(msg, m.KeyMap.Save):
+			cmd = message.SavePlan(m.Plan(), m.Tasks())
+		case key.Matches(msg, m.KeyMap.Close):
+			cmd = message.ClosePlan
+		}
+	}
+
+	m.index = max(0, min(m.index, len(m.entries)-1))
+	m.updateKeys()
+
+	return m, cmd
+}
+
+// setEstimate sets the estimate of the selected task. Estimating a task plans
+// it, since that's usually why it's being estimated.
+func (m *Model) setEstimate(estimate int) {
+	e := &m.entries[m.index]
+	e.task.Estimate = max(0, min(estimate, maxEstimate))
+	if e.task.Estimate > 0 {
+		e.planned = true
+	}
+}
+
+func (m *Model) updateKeys() {
+	selection := m.index < len(m.entries)
+
+	m.KeyMap.Up.SetEnabled(m.index > 0)
+	m.KeyMap.Down.SetEnabled(m.index+1 < len(m.entries))
+	m.KeyMap.Toggle.SetEnabled(selection)
+	m.KeyMap.More.SetEnabled(selection && m.entries[m.index].task.Estimate < maxEstimate)
+	m.KeyMap.Less.SetEnabled(selection && m.entries[m.index].task.Estimate > 0)
+}
+
+func (m *Model) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+func (m Model) View() string {
+	title := m.Styles.Title.Render("Plan for " + m.day.Format(dayFormat))
+	summary := m.viewSummary()
+
+	frameWidth, frameHeight := m.Styles.Frame.GetFrameSize()
+	height := max(0, m.height-lipgloss.Height(title)-lipgloss.Height(summary))
+	width := max(0, m.width-frameWidth)
+	rows := max(0, height-frameHeight)
+
+	var body string
+	if len(m.entries) == 0 {
+		body = m.Styles.Empty.Render("No tasks to plan")
+	} else {
+		body = m.viewTasks(width, rows)
+	}
+
+	frame := m.Styles.Frame.
+		Width(max(0, m.width-m.Styles.Frame.GetHorizontalBorderSize())).
+		Height(max(0, height-m.Styles.Frame.GetVerticalBorderSize()))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		summary,
+		frame.Render(body),
+	)
+}
+
+func (m Model) viewSummary() string {
+	plan := m.Plan()
+	estimate := plan.Estimate()
+
+	summary := fmt.Sprintf("%s planned, %s", pomo.Plural(len(plan.Tasks), "task"), pomo.Plural(estimate, "pomodoro"))
+	switch {
+	case m.goal <= 0:
+		return m.Styles.Summary.Render(summary)
+	case estimate > m.goal:
+		summary += fmt.Sprintf(": %d over the daily goal of %d", estimate-m.goal, m.goal)
+		return m.Styles.OverGoal.Render(summary)
+	default:
+		summary += fmt.Sprintf(" of the daily goal of %d", m.goal)
+		return m.Styles.Summary.Render(summary)
+	}
+}
+
+// viewTasks renders as many tasks as fit in the given rows, scrolled to keep
+// the selected task visible.
+func (m Model) viewTasks(width, rows int) string {
+	offset := max(0, m.index-rows+1)
+
+	var lines []string
+	for i := offset; i < len(m.entries) && i < offset+rows; i++ {
+		e := m.entries[i]
+
+		style := m.Styles.Task
+		if i == m.index {
+			style = m.Styles.SelectedTask
+		}
+
+		check := "[ ]"
+		if e.planned {
+			check = "[x]"
+		}
+		var estimate string
+		if e.task.Estimate > 0 {
+			estimate = m.Styles.Estimate.Render(fmt.Sprintf(" %d 🍅", e.task.Estimate))
+		}
+
+		textWidth := max(0, width-style.GetHorizontalFrameSize()-lipgloss.Width(estimate))
+		name := truncate.StringWithTail(check+" "+e.task.Name, uint(textWidth), "…")
+		lines = append(lines, style.Render(name)+estimate)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,51 @@
+package planner
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/qualidafial/pomo/color"
+)
+
+type Styles struct {
+	Title    lipgloss.Style
+	Summary  lipgloss.Style
+	OverGoal lipgloss.Style
+	Frame    lipgloss.Style
+	Empty    lipgloss.Style
+
+	Task         lipgloss.Style
+	SelectedTask lipgloss.Style
+	Estimate     lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Padding(0, 1).
+			Background(lipgloss.Color("62")).
+			Foreground(lipgloss.Color("230")),
+		Summary: lipgloss.NewStyle().
+			Padding(0, 1).
+			Foreground(color.Gray),
+		OverGoal: lipgloss.NewStyle().
+			Padding(0, 1).
+			Bold(true).
+			Foreground(color.BrightRed),
+		Frame: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")),
+		Empty: lipgloss.NewStyle().
+			Padding(0, 0, 0, 2).
+			Foreground(color.Gray),
+
+		Task: lipgloss.NewStyle().
+			Padding(0, 0, 0, 2),
+		SelectedTask: lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("170")).
+			Foreground(lipgloss.Color("170")).
+			Padding(0, 0, 0, 1),
+		Estimate: lipgloss.NewStyle().
+			Foreground(color.Gray),
+	}
+}
@@ -0,0 +1,12 @@
+package pomo
+
+import "fmt"
+
+// Plural returns the count followed by the noun, adding an "s" unless the
+// count is one, e.g. "1 task" or "3 pomodoros".
+func Plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -1,28 +1,131 @@
 package pomo
 
 import (
+	"fmt"
 	"time"
 )
 
+// Outcome records how a pomodoro in history ended.
+type Outcome int
+
+const (
+	// Completed pomodoros ran for their full duration.
+	Completed Outcome = iota
+	// Cancelled pomodoros were abandoned by the user.
+	Cancelled
+	// Interrupted pomodoros were abandoned because of an interruption.
+	Interrupted
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Completed:
+		return "completed"
+	case Cancelled:
+		return "cancelled"
+	case Interrupted:
+		return "interrupted"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseOutcome(s string) (Outcome, error) {
+	switch s {
+	case "completed":
+		return Completed, nil
+	case "cancelled":
+		return Cancelled, nil
+	case "interrupted":
+		return Interrupted, nil
+	default:
+		return 0, fmt.Errorf("unknown outcome: %s", s)
+	}
+}
+
 type Pomo struct {
 	Start time.Time `yaml:"start,omitempty"`
 	End   time.Time `yaml:"end,omitempty"`
-	Tasks []Task    `yaml:"tasks"`
+	// Remaining is the time left on the timer while paused.
+	Remaining time.Duration `yaml:"remaining,omitempty"`
+	// Pauses records each time the timer was paused. The last pause has a zero
+	// end time while the timer is paused.
+	Pauses []Pause `yaml:"pauses,omitempty"`
+	// Interruptions logged during the pomodoro.
+	Interruptions []Interruption `yaml:"interruptions,omitempty"`
+	// Outcome and Reason record how a pomodoro in history ended. For
+	// cancelled and interrupted pomodoros, End is the time it was abandoned.
+	Outcome Outcome `yaml:"outcome,omitempty"`
+	Reason  string  `yaml:"reason,omitempty"`
+	Tasks   []Task  `yaml:"tasks"`
+}
+
+// Completed returns whether the pomodoro ran for its full duration.
+func (p Pomo) Completed() bool {
+	return p.Outcome == Completed
+}
+
+// Paused returns whether the timer is currently paused.
+func (p Pomo) Paused() bool {
+	return len(p.Pauses) > 0 && p.Pauses[len(p.Pauses)-1].End.IsZero()
+}
+
+// PausedDuration returns the total time the timer spent paused, not counting
+// an ongoing pause.
+func (p Pomo) PausedDuration() time.Duration {
+	var paused time.Duration
+	for _, pause := range p.Pauses {
+		if !pause.End.IsZero() {
+			paused += pause.End.Sub(pause.Start)
+		}
+	}
+	return paused
+}
+
+// Focused returns the time spent in the pomodoro, excluding pauses.
+func (p Pomo) Focused() time.Duration {
+	return p.End.Sub(p.Start) - p.PausedDuration()
+}
+
+// CountInterruptions returns the number of interruptions of the given kind.
+func (p Pomo) CountInterruptions(kind InterruptionKind) int {
+	var count int
+	for _, i := range p.Interruptions {
+		if i.Kind == kind {
+			count++
+		}
+	}
+	return count
 }
 
 func (p Pomo) MarshalYAML() (any, error) {
-	var start, end string
+	var start, end, remaining, paused, outcome string
 	if !p.Start.IsZero() {
 		start = p.Start.Format(time.RFC3339Nano)
 	}
 	if !p.End.IsZero() {
 		end = p.End.Format(time.RFC3339Nano)
 	}
+	if p.Remaining != 0 {
+		remaining = p.Remaining.String()
+	}
+	if d := p.PausedDuration(); d != 0 {
+		paused = d.String()
+	}
+	if p.Outcome != Completed {
+		outcome = p.Outcome.String()
+	}
 
 	return pomoYaml{
-		Start: start,
-		End:   end,
-		Tasks: p.Tasks,
+		Start:         start,
+		End:           end,
+		Remaining:     remaining,
+		Paused:        paused,
+		Pauses:        p.Pauses,
+		Interruptions: p.Interruptions,
+		Outcome:       outcome,
+		Reason:        p.Reason,
+		Tasks:         p.Tasks,
 	}, nil
 }
 
@@ -43,18 +146,181 @@ func (p *Pomo) UnmarshalYAML(unmarshal func(any) error) error {
 		return err
 	}
 
+	remaining, err := parseDuration(data.Remaining)
+	if err != nil {
+		return err
+	}
+
+	// pomodoros saved without an outcome were completed
+	outcome := Completed
+	if data.Outcome != "" {
+		outcome, err = ParseOutcome(data.Outcome)
+		if err != nil {
+			return err
+		}
+	}
+
 	*p = Pomo{
+		Start:         start,
+		End:           end,
+		Remaining:     remaining,
+		Pauses:        data.Pauses,
+		Interruptions: data.Interruptions,
+		Outcome:       outcome,
+		Reason:        data.Reason,
+		Tasks:         data.Tasks,
+	}
+	return nil
+}
+
+type pomoYaml struct {
+	Start     string `yaml:"start,omitempty"`
+	End       string `yaml:"end,omitempty"`
+	Remaining string `yaml:"remaining,omitempty"`
+	// Paused is the total paused time, written for readability. Pauses is
+	// authoritative when reading.
+	Paused        string         `yaml:"paused,omitempty"`
+	Pauses        []Pause        `yaml:"pauses,omitempty"`
+	Interruptions []Interruption `yaml:"interruptions,omitempty"`
+	Outcome       string         `yaml:"outcome,omitempty"`
+	Reason        string         `yaml:"reason,omitempty"`
+	Tasks         []Task         `yaml:"tasks,omitempty"`
+}
+
+type Pause struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (p Pause) MarshalYAML() (any, error) {
+	var end string
+	if !p.End.IsZero() {
+		end = p.End.Format(time.RFC3339Nano)
+	}
+	return pauseYaml{
+		Start: p.Start.Format(time.RFC3339Nano),
+		End:   end,
+	}, nil
+}
+
+func (p *Pause) UnmarshalYAML(unmarshal func(any) error) error {
+	var data pauseYaml
+	if err := unmarshal(&data); err != nil {
+		return err
+	}
+
+	start, err := parseTime(data.Start)
+	if err != nil {
+		return err
+	}
+
+	end, err := parseTime(data.End)
+	if err != nil {
+		return err
+	}
+
+	*p = Pause{
 		Start: start,
 		End:   end,
-		Tasks: data.Tasks,
 	}
 	return nil
 }
 
-type pomoYaml struct {
-	Start string `yaml:"start,omitempty"`
+type pauseYaml struct {
+	Start string `yaml:"start"`
 	End   string `yaml:"end,omitempty"`
-	Tasks []Task `yaml:"tasks,omitempty"`
+}
+
+// InterruptionKind distinguishes interruptions that come from the person
+// doing the work from those that come from someone else.
+type InterruptionKind int
+
+const (
+	// Internal interruptions are distractions by the person doing the work,
+	// marked with an apostrophe (') in the classic Pomodoro technique.
+	Internal InterruptionKind = iota
+	// External interruptions come from other people, marked with a dash (-) in
+	// the classic Pomodoro technique.
+	External
+)
+
+func (k InterruptionKind) String() string {
+	switch k {
+	case Internal:
+		return "internal"
+	case External:
+		return "external"
+	default:
+		return "unknown"
+	}
+}
+
+// Mark returns the classic Pomodoro technique mark for the kind of
+// interruption.
+func (k InterruptionKind) Mark() string {
+	switch k {
+	case Internal:
+		return "'"
+	case External:
+		return "-"
+	default:
+		return "?"
+	}
+}
+
+func ParseInterruptionKind(s string) (InterruptionKind, error) {
+	switch s {
+	case "internal":
+		return Internal, nil
+	case "external":
+		return External, nil
+	default:
+		return 0, fmt.Errorf("unknown interruption kind: %s", s)
+	}
+}
+
+type Interruption struct {
+	Time time.Time
+	Kind InterruptionKind
+	Note string
+}
+
+func (i Interruption) MarshalYAML() (any, error) {
+	return interruptionYaml{
+		Time: i.Time.Format(time.RFC3339Nano),
+		Kind: i.Kind.String(),
+		Note: i.Note,
+	}, nil
+}
+
+func (i *Interruption) UnmarshalYAML(unmarshal func(any) error) error {
+	var data interruptionYaml
+	if err := unmarshal(&data); err != nil {
+		return err
+	}
+
+	t, err := parseTime(data.Time)
+	if err != nil {
+		return err
+	}
+
+	kind, err := ParseInterruptionKind(data.Kind)
+	if err != nil {
+		return err
+	}
+
+	*i = Interruption{
+		Time: t,
+		Kind: kind,
+		Note: data.Note,
+	}
+	return nil
+}
+
+type interruptionYaml struct {
+	Time string `yaml:"time"`
+	Kind string `yaml:"kind"`
+	Note string `yaml:"note,omitempty"`
 }
 
 func parseTime(s string) (time.Time, error) {
@@ -63,3 +329,10 @@ func parseTime(s string) (time.Time, error) {
 	}
 	return time.Parse(time.RFC3339Nano, s)
 }
+
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
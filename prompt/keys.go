@@ -7,6 +7,10 @@ import (
 type KeyMap struct {
 	Yes key.Binding
 	No  key.Binding
+
+	// Submit and Cancel replace Yes and No when the prompt has an input.
+	Submit key.Binding
+	Cancel key.Binding
 }
 
 func DefaultKeyMap() KeyMap {
@@ -19,6 +23,17 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("n", "esc"),
 			key.WithHelp("n/esc", "no"),
 		),
+
+		Submit: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "yes"),
+			key.WithDisabled(),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "no"),
+			key.WithDisabled(),
+		),
 	}
 }
 
@@ -27,6 +42,8 @@ func (m KeyMap) FullHelp() [][]key.Binding {
 		{
 			m.Yes,
 			m.No,
+			m.Submit,
+			m.Cancel,
 		},
 	}
 }
@@ -35,5 +52,7 @@ func (m KeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{
 		m.Yes,
 		m.No,
+		m.Submit,
+		m.Cancel,
 	}
 }
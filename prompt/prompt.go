@@ -5,6 +5,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/reflow/wordwrap"
@@ -31,6 +32,9 @@ type Model struct {
 
 	Prompt string
 
+	withInput bool
+	input     textinput.Model
+
 	help help.Model
 }
 
@@ -43,6 +47,8 @@ func New() Model {
 		maxWidth: 80,
 		Prompt:   "",
 
+		input: textinput.New(),
+
 		help: help.New(),
 	}
 }
@@ -57,31 +63,78 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, m.KeyMap.Yes):
+		case key.Matches(msg, m.KeyMap.Yes) || key.Matches(msg, m.KeyMap.Submit):
+			value := m.Value()
 			cmd = func() tea.Msg {
 				return ConfirmMsg{
-					ID: m.id,
+					ID:    m.id,
+					Value: value,
 				}
 			}
-		case key.Matches(msg, m.KeyMap.No):
+		case key.Matches(msg, m.KeyMap.No) || key.Matches(msg, m.KeyMap.Cancel):
 			cmd = func() tea.Msg {
 				return CancelMsg{
 					ID: m.id,
 				}
 			}
+		case m.withInput:
+			m.input, cmd = m.input.Update(msg)
+		}
+	default:
+		if m.withInput {
+			m.input, cmd = m.input.Update(msg)
 		}
 	}
 
 	return m, cmd
 }
 
+// SetInput adds a single line text input to the prompt, with the given
+// placeholder. The value entered is returned in ConfirmMsg.
+func (m *Model) SetInput(placeholder string) tea.Cmd {
+	m.withInput = true
+	m.input.Reset()
+	m.input.Placeholder = placeholder
+	m.enableKeys()
+	return m.input.Focus()
+}
+
+// ClearInput removes the text input from the prompt.
+func (m *Model) ClearInput() {
+	m.withInput = false
+	m.input.Reset()
+	m.input.Blur()
+	m.enableKeys()
+}
+
+// Value returns the value of the text input, if any.
+func (m Model) Value() string {
+	if !m.withInput {
+		return ""
+	}
+	return m.input.Value()
+}
+
+func (m *Model) enableKeys() {
+	m.KeyMap.Yes.SetEnabled(!m.withInput)
+	m.KeyMap.No.SetEnabled(!m.withInput)
+	m.KeyMap.Submit.SetEnabled(m.withInput)
+	m.KeyMap.Cancel.SetEnabled(m.withInput)
+}
+
 func (m Model) View() string {
+	sections := []string{
+		m.viewPrompt(),
+		"",
+	}
+	if m.withInput {
+		m.input.Width = m.maxWidth - m.Styles.Frame.GetHorizontalFrameSize() - 3
+		sections = append(sections, m.input.View(), "")
+	}
+	sections = append(sections, m.viewHelp())
+
 	return m.Styles.Frame.Render(
-		lipgloss.JoinVertical(lipgloss.Left,
-			m.viewPrompt(),
-			"",
-			m.viewHelp(),
-		),
+		lipgloss.JoinVertical(lipgloss.Left, sections...),
 	)
 }
 
@@ -109,6 +162,8 @@ type initMsg struct {
 
 type ConfirmMsg struct {
 	ID int
+	// Value is the text entered, if the prompt has an input.
+	Value string
 }
 
 type CancelMsg struct {
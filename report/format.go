@@ -0,0 +1,197 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+type Format int
+
+const (
+	Table Format = iota
+	JSON
+	CSV
+	Markdown
+)
+
+func (f Format) String() string {
+	switch f {
+	case Table:
+		return "table"
+	case JSON:
+		return "json"
+	case CSV:
+		return "csv"
+	case Markdown:
+		return "markdown"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "table":
+		return Table, nil
+	case "json":
+		return JSON, nil
+	case "csv":
+		return CSV, nil
+	case "markdown", "md":
+		return Markdown, nil
+	default:
+		return 0, fmt.Errorf("unknown format: %s", s)
+	}
+}
+
+// Write writes the report to w in the given format.
+func Write(w io.Writer, r Report, format Format) error {
+	switch format {
+	case Table:
+		return writeTable(w, r)
+	case JSON:
+		return writeJSON(w, r)
+	case CSV:
+		return writeCSV(w, r)
+	case Markdown:
+		return writeMarkdown(w, r)
+	default:
+		return fmt.Errorf("unknown format: %v", format)
+	}
+}
+
+func (r Report) header() []string {
+	header := []string{r.GroupBy.String(), "pomos", "minutes", "cancelled", "interrupted"}
+	if r.GroupBy != ByTask {
+		header = append(header, "tasks")
+	}
+	return header
+}
+
+func (r Report) record(row Row) []string {
+	record := []string{
+		row.Key,
+		strconv.Itoa(row.Pomos),
+		strconv.Itoa(row.Minutes()),
+		strconv.Itoa(row.Cancelled),
+		strconv.Itoa(row.Interrupted),
+	}
+	if r.GroupBy != ByTask {
+		record = append(record, row.TaskSummary())
+	}
+	return record
+}
+
+func (r Report) records() [][]string {
+	var records [][]string
+	for _, row := range r.Rows {
+		records = append(records, r.record(row))
+	}
+	total := r.Total
+	total.Key = "total"
+	return append(records, r.record(total))
+}
+
+func writeTable(w io.Writer, r Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := r.header()
+	for i := range header {
+		header[i] = strings.ToUpper(header[i])
+	}
+	_, err := fmt.Fprintln(tw, strings.Join(header, "\t"))
+	if err != nil {
+		return err
+	}
+	for _, record := range r.records() {
+		_, err = fmt.Fprintln(tw, strings.Join(record, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write(r.header())
+	if err != nil {
+		return err
+	}
+	return cw.WriteAll(r.records())
+}
+
+func writeMarkdown(w io.Writer, r Report) error {
+	header := r.header()
+	separator := make([]string, len(header))
+	for i := range header {
+		separator[i] = "---"
+		if i > 0 && i < 5 {
+			separator[i] = "---:"
+		}
+	}
+
+	lines := []string{
+		markdownRow(header),
+		markdownRow(separator),
+	}
+	for _, record := range r.records() {
+		lines = append(lines, markdownRow(record))
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(cell, "|", `\|`)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
+
+type jsonReport struct {
+	GroupBy string    `json:"groupBy"`
+	Rows    []jsonRow `json:"rows"`
+	Total   jsonRow   `json:"total"`
+}
+
+type jsonRow struct {
+	Key         string      `json:"key,omitempty"`
+	Pomos       int         `json:"pomos"`
+	Minutes     int         `json:"minutes"`
+	Cancelled   int         `json:"cancelled"`
+	Interrupted int         `json:"interrupted"`
+	Tasks       []TaskCount `json:"tasks,omitempty"`
+}
+
+func toJSONRow(row Row) jsonRow {
+	return jsonRow{
+		Key:         row.Key,
+		Pomos:       row.Pomos,
+		Minutes:     row.Minutes(),
+		Cancelled:   row.Cancelled,
+		Interrupted: row.Interrupted,
+		Tasks:       row.Tasks,
+	}
+}
+
+func writeJSON(w io.Writer, r Report) error {
+	data := jsonReport{
+		GroupBy: r.GroupBy.String(),
+		Rows:    []jsonRow{},
+		Total:   toJSONRow(r.Total),
+	}
+	for _, row := range r.Rows {
+		data.Rows = append(data.Rows, toJSONRow(row))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
@@ -0,0 +1,213 @@
+// Package report summarizes completed pomodoros for display outside the TUI.
+package report
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qualidafial/pomo"
+)
+
+type GroupBy int
+
+const (
+	ByDay GroupBy = iota
+	ByWeek
+	ByTask
+)
+
+func (g GroupBy) String() string {
+	switch g {
+	case ByDay:
+		return "day"
+	case ByWeek:
+		return "week"
+	case ByTask:
+		return "task"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseGroupBy(s string) (GroupBy, error) {
+	switch s {
+	case "day":
+		return ByDay, nil
+	case "week":
+		return ByWeek, nil
+	case "task":
+		return ByTask, nil
+	default:
+		return 0, fmt.Errorf("unknown grouping: %s", s)
+	}
+}
+
+// Report is a summary of pomodoros grouped by period or by task.
+type Report struct {
+	GroupBy GroupBy
+	Rows    []Row
+	Total   Row
+}
+
+// Row summarizes the pomodoros in a single group.
+type Row struct {
+	// Key is the day (2006-01-02), ISO week (2006-W01) or task name.
+	Key string
+	// Pomos is the number of completed pomodoros.
+	Pomos int
+	// Cancelled and Interrupted are the number of abandoned pomodoros.
+	Cancelled   int
+	Interrupted int
+	// Focused is the total time spent in the group's completed pomodoros,
+	// excluding pauses.
+	Focused time.Duration
+	// Tasks holds the number of pomodoros each task was worked on within the
+	// group. Empty when grouping by task.
+	Tasks []TaskCount
+}
+
+type TaskCount struct {
+	Name  string `json:"name"`
+	Pomos int    `json:"pomos"`
+}
+
+// New summarizes the given pomodoros. Periods are computed in the location of
+// each pomodoro's start time.
+func New(pomos []pomo.Pomo, groupBy GroupBy) Report {
+	r := Report{
+		GroupBy: groupBy,
+	}
+
+	rows := map[string]*row{}
+	var keys []string
+	getRow := func(key string) *row {
+		rw, ok := rows[key]
+		if !ok {
+			rw = &row{
+				Row: Row{
+					Key: key,
+				},
+				tasks: map[string]*TaskCount{},
+			}
+			rows[key] = rw
+			keys = append(keys, key)
+		}
+		return rw
+	}
+
+	// tasks are matched by ID so renamed tasks are counted together under
+	// their most recent name.
+	names := map[string]string{}
+	for _, p := range pomos {
+		for _, task := range p.Tasks {
+			names[taskKey(task)] = task.Name
+		}
+	}
+
+	for _, p := range pomos {
+		r.Total.add(p)
+
+		switch groupBy {
+		case ByDay, ByWeek:
+			rw := getRow(periodKey(p.Start, groupBy))
+			rw.add(p)
+			if p.Completed() {
+				for _, task := range p.Tasks {
+					rw.addTask(taskKey(task), names[taskKey(task)])
+				}
+			}
+		case ByTask:
+			for _, task := range p.Tasks {
+				rw := getRow(taskKey(task))
+				rw.Key = names[taskKey(task)]
+				rw.add(p)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		r.Rows = append(r.Rows, rows[key].build())
+	}
+
+	if groupBy == ByTask {
+		slices.SortStableFunc(r.Rows, func(a, b Row) int {
+			return b.Pomos - a.Pomos
+		})
+	}
+
+	return r
+}
+
+type row struct {
+	Row
+	taskOrder []string
+	tasks     map[string]*TaskCount
+}
+
+func (r *Row) add(p pomo.Pomo) {
+	switch p.Outcome {
+	case pomo.Completed:
+		r.Pomos++
+		r.Focused += p.Focused()
+	case pomo.Cancelled:
+		r.Cancelled++
+	case pomo.Interrupted:
+		r.Interrupted++
+	}
+}
+
+func (r *row) addTask(key, name string) {
+	tc, ok := r.tasks[key]
+	if !ok {
+		tc = &TaskCount{
+			Name: name,
+		}
+		r.tasks[key] = tc
+		r.taskOrder = append(r.taskOrder, key)
+	}
+	tc.Pomos++
+}
+
+func (r *row) build() Row {
+	result := r.Row
+	for _, key := range r.taskOrder {
+		result.Tasks = append(result.Tasks, *r.tasks[key])
+	}
+	slices.SortStableFunc(result.Tasks, func(a, b TaskCount) int {
+		return b.Pomos - a.Pomos
+	})
+	return result
+}
+
+func taskKey(task pomo.Task) string {
+	if task.ID != "" {
+		return task.ID
+	}
+	// tasks saved before IDs were introduced can only be matched by name
+	return "name:" + task.Name
+}
+
+func periodKey(t time.Time, groupBy GroupBy) string {
+	if groupBy == ByWeek {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	}
+	return t.Format(time.DateOnly)
+}
+
+// Minutes returns the focused time of the row in whole minutes.
+func (r Row) Minutes() int {
+	return int(r.Focused.Round(time.Minute) / time.Minute)
+}
+
+// TaskSummary returns the per-task pomodoro counts of the row as a single line,
+// e.g. "Wax the car (2), Paint the fence (1)".
+func (r Row) TaskSummary() string {
+	var parts []string
+	for _, task := range r.Tasks {
+		parts = append(parts, fmt.Sprintf("%s (%d)", task.Name, task.Pomos))
+	}
+	return strings.Join(parts, ", ")
+}
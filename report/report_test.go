@@ -0,0 +1,73 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	day1 := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	fence := pomo.Task{ID: "a1", Status: pomo.Doing, Name: "Paint the fence"}
+	car := pomo.Task{ID: "b2", Status: pomo.Done, Name: "Wax the car"}
+	renamed := car
+	renamed.Name = "Wax and polish the car"
+
+	pomos := []pomo.Pomo{
+		{Start: day1, End: day1.Add(25 * time.Minute), Tasks: []pomo.Task{fence, car}},
+		{Start: day1.Add(time.Hour), End: day1.Add(time.Hour + 25*time.Minute), Tasks: []pomo.Task{fence}},
+		{Start: day2, End: day2.Add(25 * time.Minute), Tasks: []pomo.Task{renamed}},
+		{Start: day2.Add(time.Hour), End: day2.Add(time.Hour + 10*time.Minute), Outcome: pomo.Interrupted, Tasks: []pomo.Task{fence}},
+	}
+
+	t.Run("by day", func(t *testing.T) {
+		r := report.New(pomos, report.ByDay)
+		require.Len(t, r.Rows, 2)
+		assert.Equal(t, "2024-03-04", r.Rows[0].Key)
+		assert.Equal(t, 2, r.Rows[0].Pomos)
+		assert.Equal(t, 50, r.Rows[0].Minutes())
+		assert.Equal(t, "Paint the fence (2), Wax and polish the car (1)", r.Rows[0].TaskSummary())
+		assert.Equal(t, "2024-03-05", r.Rows[1].Key)
+		assert.Equal(t, 1, r.Rows[1].Pomos)
+		assert.Equal(t, 1, r.Rows[1].Interrupted)
+		assert.Equal(t, "Wax and polish the car (1)", r.Rows[1].TaskSummary())
+		assert.Equal(t, 3, r.Total.Pomos)
+		assert.Equal(t, 75, r.Total.Minutes())
+	})
+
+	t.Run("by week", func(t *testing.T) {
+		r := report.New(pomos, report.ByWeek)
+		require.Len(t, r.Rows, 1)
+		assert.Equal(t, "2024-W10", r.Rows[0].Key)
+		assert.Equal(t, 3, r.Rows[0].Pomos)
+	})
+
+	t.Run("by task", func(t *testing.T) {
+		r := report.New(pomos, report.ByTask)
+		require.Len(t, r.Rows, 2)
+		assert.Equal(t, "Paint the fence", r.Rows[0].Key)
+		assert.Equal(t, 2, r.Rows[0].Pomos)
+		assert.Equal(t, "Wax and polish the car", r.Rows[1].Key)
+		assert.Equal(t, 2, r.Rows[1].Pomos)
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := report.Write(&buf, report.New(pomos, report.ByTask), report.Markdown)
+		require.NoError(t, err)
+		assert.Equal(t, ""+
+			"| task | pomos | minutes | cancelled | interrupted |\n"+
+			"| --- | ---: | ---: | ---: | ---: |\n"+
+			"| Paint the fence | 2 | 50 | 0 | 1 |\n"+
+			"| Wax and polish the car | 2 | 50 | 0 | 0 |\n"+
+			"| total | 3 | 75 | 0 | 1 |\n",
+			buf.String())
+	})
+}
@@ -0,0 +1,60 @@
+package session
+
+import (
+	"github.com/qualidafial/pomo"
+)
+
+// Event drives a transition of the session from one state to another.
+type Event interface {
+	// String describes the transition, e.g. "start a pomodoro".
+	String() string
+	event()
+}
+
+// Start starts a pomodoro.
+type Start struct{}
+
+// Cancel abandons the active pomodoro.
+type Cancel struct {
+	Outcome pomo.Outcome
+	Reason  string
+}
+
+// Complete completes an ended pomodoro and starts the break.
+type Complete struct{}
+
+// CancelBreak ends a break early.
+type CancelBreak struct{}
+
+// Pause freezes the running pomodoro or break timer.
+type Pause struct{}
+
+// Resume restarts a paused pomodoro or break timer.
+type Resume struct{}
+
+// Timeout signals that the pomodoro or break timer has run out.
+type Timeout struct{}
+
+// Interrupt logs an interruption during the active pomodoro.
+type Interrupt struct {
+	Kind pomo.InterruptionKind
+	Note string
+}
+
+func (Start) String() string       { return "start a pomodoro" }
+func (Cancel) String() string      { return "cancel a pomodoro" }
+func (Complete) String() string    { return "complete a pomodoro" }
+func (CancelBreak) String() string { return "cancel a break" }
+func (Pause) String() string       { return "pause the timer" }
+func (Resume) String() string      { return "resume the timer" }
+func (Timeout) String() string     { return "time out" }
+func (Interrupt) String() string   { return "log an interruption" }
+
+func (Start) event()       {}
+func (Cancel) event()      {}
+func (Complete) event()    {}
+func (CancelBreak) event() {}
+func (Pause) event()       {}
+func (Resume) event()      {}
+func (Timeout) event()     {}
+func (Interrupt) event()   {}
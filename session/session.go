@@ -0,0 +1,478 @@
+// Package session holds the rules of the pomodoro cycle: idle, pomodoro,
+// break, and back again.
+package session
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/config"
+)
+
+// ErrNotAllowed is returned for events that are not allowed in the current
+// state.
+var ErrNotAllowed = errors.New("not allowed")
+
+type State int
+
+const (
+	// No timer is running
+	Idle State = iota
+	// A pomodoro timer is running
+	Active
+	// A pomodoro timer has finished and the user is reporting what they did
+	Ended
+	// A short break timer is running
+	Break
+	// A long break timer is running
+	LongBreak
+	// A break timer has finished
+	BreakEnded
+)
+
+func (s State) String() string {
+	switch s {
+	case Idle:
+		return "idle"
+	case Active:
+		return "active"
+	case Ended:
+		return "ended"
+	case Break:
+		return "break"
+	case LongBreak:
+		return "long-break"
+	case BreakEnded:
+		return "break-ended"
+	default:
+		return "unknown"
+	}
+}
+
+// Timing returns whether a pomodoro or break timer is running or paused in
+// this state.
+func (s State) Timing() bool {
+	return s == Active || s == Break || s == LongBreak
+}
+
+// LongBreakDue returns whether the break after the given number of pomodoros
+// completed today is a long break.
+func LongBreakDue(completed, longBreakEvery int) bool {
+	return longBreakEvery > 0 && completed > 0 && completed%longBreakEvery == 0
+}
+
+// Infer infers the state of the current pomodoro from its start and end
+// dates, given the number of pomodoros completed today:
+//
+//	state        start          end
+//	==================================
+//	paused       n/a            set for pomodoros, zero for breaks
+//	idle         zero           n/a
+//	break        future         n/a
+//	active       past           future
+//	ended        past           past
+//	idle         before today   zero (a break that ended yesterday)
+//	break ended  earlier today  zero (a break whose resume time has passed)
+func Infer(current pomo.Pomo, completed, longBreakEvery int, now time.Time) State {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	// infer break vs long break by the number of completed pomos today
+	onBreak := Break
+	if LongBreakDue(completed, longBreakEvery) {
+		onBreak = LongBreak
+	}
+
+	switch {
+	case current.Paused():
+		// paused pomodoros have an end date, paused breaks do not
+		if current.End.IsZero() {
+			return onBreak
+		}
+		return Active
+	case current.Start.IsZero():
+		// no start date: idle
+		return Idle
+	case current.Start.Compare(now) >= 0:
+		// start > now: on a break
+		return onBreak
+	// start < now guaranteed from here on
+	case current.End.After(now):
+		// start < now < end: in an active pomodoro
+		return Active
+	case !current.End.IsZero():
+		// start < end < now: pomodoro has ended
+		return Ended
+	// end is guaranteed empty from here on
+	case current.Start.After(today):
+		// today < start < now: break whose resume time was earlier today
+		return BreakEnded
+	default:
+		// today < start: break whose resume time was before today
+		return Idle
+	}
+}
+
+// End returns when the running pomodoro or break timer ends. Returns false if
+// no timer is running.
+func End(current pomo.Pomo, state State) (time.Time, bool) {
+	if current.Paused() {
+		return time.Time{}, false
+	}
+	// a pomodoro ends at its end time, a break ends at the next start time
+	switch state {
+	case Active:
+		return current.End, true
+	case Break, LongBreak:
+		return current.Start, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// Remaining returns the time left on the running or paused timer.
+func Remaining(current pomo.Pomo, state State, now time.Time) time.Duration {
+	if current.Paused() {
+		return current.Remaining
+	}
+	end, ok := End(current, state)
+	if !ok {
+		return 0
+	}
+	return max(0, end.Sub(now))
+}
+
+// Describe returns a short description of the state, e.g. "pomo 3 in
+// progress", given the number of pomodoros completed today.
+func Describe(state State, paused bool, completed int) string {
+	switch state {
+	case Idle:
+		return "idle"
+	case Ended:
+		return fmt.Sprintf("pomo %d ended -- report tasks", completed+1)
+	case BreakEnded:
+		return "break ended -- start another pomo"
+	case Active:
+		if paused {
+			return fmt.Sprintf("pomo %d paused", completed+1)
+		}
+		return fmt.Sprintf("pomo %d in progress", completed+1)
+	case Break:
+		if paused {
+			return "break paused"
+		}
+		return "on a break"
+	case LongBreak:
+		if paused {
+			return "long break paused"
+		}
+		return "on a long break"
+	default:
+		return "unknown"
+	}
+}
+
+// Session tracks the current pomodoro or break and the pomodoros completed
+// today, and applies events to move between states.
+type Session struct {
+	config config.Config
+	clock  clock.Clock
+
+	state     State
+	current   pomo.Pomo
+	completed []pomo.Pomo
+}
+
+// Result describes the effects of a transition for the caller to persist.
+type Result struct {
+	// Changed is set when the current pomodoro was modified.
+	Changed bool
+	// Finished is a completed or abandoned pomodoro to be saved to history.
+	Finished *pomo.Pomo
+	// Archived holds the done tasks removed from the board when a pomodoro is
+	// completed.
+	Archived []pomo.Task
+}
+
+// New creates an idle session. The clock times all transitions.
+func New(cfg config.Config, c clock.Clock) Session {
+	return Session{
+		config: cfg,
+		clock:  c,
+		state:  Idle,
+	}
+}
+
+func (s Session) State() State {
+	return s.state
+}
+
+// Current returns the current pomodoro or break, along with the task board.
+func (s Session) Current() pomo.Pomo {
+	return s.current
+}
+
+// Completed returns the pomodoros completed today.
+func (s Session) Completed() []pomo.Pomo {
+	return s.completed
+}
+
+func (s Session) Paused() bool {
+	return s.current.Paused()
+}
+
+// End returns when the running pomodoro or break timer ends. Returns false if
+// no timer is running.
+func (s Session) End() (time.Time, bool) {
+	return End(s.current, s.state)
+}
+
+// Remaining returns the time left on the running or paused timer.
+func (s Session) Remaining() time.Duration {
+	return Remaining(s.current, s.state, s.clock.Now())
+}
+
+// Describe returns a short description of the state, e.g. "pomo 3 in
+// progress".
+func (s Session) Describe() string {
+	return Describe(s.state, s.Paused(), len(s.completed))
+}
+
+// SetTasks replaces the tasks on the board.
+func (s *Session) SetTasks(tasks []pomo.Task) {
+	s.current.Tasks = tasks
+}
+
+// Load restores the session from the saved current pomodoro and the pomodoros
+// completed today, inferring the state from the saved start and end dates.
+func (s *Session) Load(current pomo.Pomo, completed []pomo.Pomo) Result {
+	var result Result
+
+	s.current = current
+	s.completed = completed
+	s.state = Infer(current, len(completed), s.config.LongBreakEvery, s.clock.Now())
+
+	if s.state == Idle && !s.current.Start.IsZero() {
+		// a break whose resume time was before today
+		s.clearTimer()
+		result.Changed = true
+	}
+
+	return result
+}
+
+// AutoStart applies auto-start options to a loaded pomodoro or break that
+// ended while the app was closed, without starting anything the user would
+// have missed.
+func (s *Session) AutoStart() Result {
+	var result Result
+
+	now := s.clock.Now()
+	switch {
+	case s.state == Ended && s.config.AutoStartBreak:
+		s.complete(&result, s.current.End)
+		result.Changed = true
+	case s.state == BreakEnded && s.config.AutoStartPomodoro:
+		if s.current.Start.Add(s.config.PomodoroDuration).After(now) {
+			s.start(s.current.Start)
+			result.Changed = true
+		}
+	}
+
+	return result
+}
+
+// Allowed returns an error wrapping ErrNotAllowed if the event is not allowed
+// in the current state.
+func (s Session) Allowed(e Event) error {
+	var allowed bool
+	switch e.(type) {
+	case Start:
+		allowed = s.state == Idle || s.state == BreakEnded
+	case Cancel, Interrupt:
+		allowed = s.state == Active
+	case Complete:
+		allowed = s.state == Ended
+	case CancelBreak:
+		allowed = s.state == Break || s.state == LongBreak
+	case Pause, Timeout:
+		allowed = s.state.Timing() && !s.Paused()
+	case Resume:
+		allowed = s.state.Timing() && s.Paused()
+	}
+	if !allowed {
+		return fmt.Errorf("%w: cannot %s while %s", ErrNotAllowed, e, s.Describe())
+	}
+	return nil
+}
+
+// Handle applies the event to the session.
+func (s *Session) Handle(e Event) (Result, error) {
+	err := s.Allowed(e)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		Changed: true,
+	}
+	now := s.clock.Now()
+
+	switch e := e.(type) {
+	case Start:
+		s.start(now)
+	case Cancel:
+		result.Finished = s.cancel(now, e.Outcome, e.Reason)
+	case Complete:
+		s.complete(&result, now)
+	case CancelBreak:
+		s.state = Idle
+		s.clearTimer()
+	case Pause:
+		s.current.Remaining = s.Remaining()
+		s.current.Pauses = append(s.current.Pauses, pomo.Pause{
+			Start: now,
+		})
+	case Resume:
+		s.resume(now)
+	case Timeout:
+		s.timeout(&result)
+	case Interrupt:
+		s.current.Interruptions = append(s.current.Interruptions, pomo.Interruption{
+			Time: now,
+			Kind: e.Kind,
+			Note: e.Note,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Session) start(start time.Time) {
+	s.state = Active
+	s.clearTimer()
+	s.current.Start = start
+	s.current.End = start.Add(s.config.PomodoroDuration)
+}
+
+// cancel abandons the active pomodoro with the given outcome and returns to
+// idle. Returns the abandoned pomodoro.
+func (s *Session) cancel(now time.Time, outcome pomo.Outcome, reason string) *pomo.Pomo {
+	abandoned := pomo.Pomo{
+		Start:         s.current.Start,
+		End:           now,
+		Pauses:        slices.Clone(s.current.Pauses),
+		Interruptions: s.current.Interruptions,
+		Outcome:       outcome,
+		Reason:        reason,
+		Tasks:         s.workedOn(),
+	}
+	if abandoned.Paused() {
+		// end the ongoing pause so it is excluded from the elapsed time
+		abandoned.Pauses[len(abandoned.Pauses)-1].End = now
+	}
+
+	s.state = Idle
+	s.clearTimer()
+	return &abandoned
+}
+
+// complete completes the ended pomodoro, removes done tasks from the board and
+// starts a break at the given time. The completed pomodoro and the removed
+// tasks are added to the result.
+func (s *Session) complete(result *Result, breakStart time.Time) {
+	var incomplete, done []pomo.Task
+	for _, task := range s.current.Tasks {
+		if s.config.Workflow.Done(task.Status) {
+			done = append(done, task)
+		} else {
+			incomplete = append(incomplete, task)
+		}
+	}
+
+	completed := pomo.Pomo{
+		Start:         s.current.Start,
+		End:           s.current.End,
+		Pauses:        s.current.Pauses,
+		Interruptions: s.current.Interruptions,
+		Tasks:         s.workedOn(),
+	}
+	s.completed = append(s.completed, completed)
+
+	s.state = Break
+	duration := s.config.BreakDuration
+	if LongBreakDue(len(s.completed), s.config.LongBreakEvery) {
+		s.state = LongBreak
+		duration = s.config.LongBreakDuration
+	}
+	breakEnd := breakStart.Add(duration)
+	s.clearTimer()
+	s.current.Start = breakEnd
+	s.current.Tasks = incomplete
+
+	if !breakEnd.After(s.clock.Now()) {
+		// the break already ended while the app was closed
+		s.state = BreakEnded
+	}
+
+	result.Finished = &completed
+	result.Archived = done
+}
+
+// resume restarts a paused pomodoro or break timer with the time that was
+// remaining when it was paused.
+func (s *Session) resume(now time.Time) {
+	s.current.Pauses[len(s.current.Pauses)-1].End = now
+	end := now.Add(s.current.Remaining)
+	s.current.Remaining = 0
+
+	// a pomodoro ends at its end time, a break ends at the next start time
+	if s.state == Active {
+		s.current.End = end
+	} else {
+		s.current.Start = end
+	}
+}
+
+// timeout ends the running pomodoro or break, applying auto-start options.
+func (s *Session) timeout(result *Result) {
+	switch s.state {
+	case Active:
+		if s.config.AutoStartBreak {
+			s.complete(result, s.current.End)
+			return
+		}
+		s.state = Ended
+	case Break, LongBreak:
+		if s.config.AutoStartPomodoro {
+			s.start(s.current.Start)
+		} else {
+			s.state = BreakEnded
+		}
+	}
+}
+
+// clearTimer clears the start, end and pause state of the current pomodoro or
+// break.
+func (s *Session) clearTimer() {
+	s.current.Start = time.Time{}
+	s.current.End = time.Time{}
+	s.current.Remaining = 0
+	s.current.Pauses = nil
+	s.current.Interruptions = nil
+}
+
+// workedOn returns the tasks in the columns that count as worked on.
+func (s Session) workedOn() []pomo.Task {
+	var result []pomo.Task
+	for _, task := range s.current.Tasks {
+		if s.config.Workflow.WorkedOn(task.Status) {
+			result = append(result, task)
+		}
+	}
+	return result
+}
@@ -0,0 +1,390 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/config"
+	"github.com/qualidafial/pomo/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+func completedPomos(n int) []pomo.Pomo {
+	pomos := make([]pomo.Pomo, n)
+	for i := range pomos {
+		pomos[i] = pomo.Pomo{
+			Start: now.Add(-time.Duration(n-i) * time.Hour),
+			End:   now.Add(-time.Duration(n-i)*time.Hour + 25*time.Minute),
+		}
+	}
+	return pomos
+}
+
+func TestInfer(t *testing.T) {
+	yesterday := now.AddDate(0, 0, -1)
+
+	tests := map[string]struct {
+		current   pomo.Pomo
+		completed int
+		want      session.State
+	}{
+		"idle": {
+			want: session.Idle,
+		},
+		"active": {
+			current: pomo.Pomo{
+				Start: now.Add(-10 * time.Minute),
+				End:   now.Add(15 * time.Minute),
+			},
+			want: session.Active,
+		},
+		"ended": {
+			current: pomo.Pomo{
+				Start: now.Add(-30 * time.Minute),
+				End:   now.Add(-5 * time.Minute),
+			},
+			want: session.Ended,
+		},
+		"break": {
+			current: pomo.Pomo{
+				Start: now.Add(3 * time.Minute),
+			},
+			completed: 1,
+			want:      session.Break,
+		},
+		"long break": {
+			current: pomo.Pomo{
+				Start: now.Add(10 * time.Minute),
+			},
+			completed: 4,
+			want:      session.LongBreak,
+		},
+		"break ended": {
+			current: pomo.Pomo{
+				Start: now.Add(-time.Minute),
+			},
+			completed: 1,
+			want:      session.BreakEnded,
+		},
+		"paused pomodoro": {
+			current: pomo.Pomo{
+				Start:     now.Add(-time.Hour),
+				End:       now.Add(-30 * time.Minute),
+				Remaining: 10 * time.Minute,
+				Pauses: []pomo.Pause{
+					{Start: now.Add(-45 * time.Minute)},
+				},
+			},
+			want: session.Active,
+		},
+		"paused break": {
+			current: pomo.Pomo{
+				Start:     now.Add(-time.Hour),
+				Remaining: 2 * time.Minute,
+				Pauses: []pomo.Pause{
+					{Start: now.Add(-time.Hour)},
+				},
+			},
+			completed: 1,
+			want:      session.Break,
+		},
+		"break resumed after midnight": {
+			current: pomo.Pomo{
+				Start: yesterday.Add(5 * time.Minute),
+			},
+			want: session.Idle,
+		},
+		"pomodoro ended before midnight": {
+			current: pomo.Pomo{
+				Start: yesterday.Add(-25 * time.Minute),
+				End:   yesterday,
+			},
+			want: session.Ended,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := session.Infer(tc.current, tc.completed, 4, now)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestLongBreakDue(t *testing.T) {
+	assert.False(t, session.LongBreakDue(0, 4))
+	assert.False(t, session.LongBreakDue(3, 4))
+	assert.True(t, session.LongBreakDue(4, 4))
+	assert.True(t, session.LongBreakDue(8, 4))
+	assert.False(t, session.LongBreakDue(4, 0))
+}
+
+func TestSession_Cycle(t *testing.T) {
+	c := clock.NewFake(now)
+	s := session.New(config.Default(), c)
+	s.Load(pomo.Pomo{}, completedPomos(3))
+	s.SetTasks([]pomo.Task{
+		{ID: "a", Status: pomo.Todo, Name: "Paint the fence"},
+		{ID: "b", Status: pomo.Doing, Name: "Wax the car"},
+		{ID: "c", Status: pomo.Done, Name: "Sand the floor"},
+	})
+	require.Equal(t, session.Idle, s.State())
+
+	result, err := s.Handle(session.Start{})
+	require.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Nil(t, result.Finished)
+	assert.Equal(t, session.Active, s.State())
+	end, ok := s.End()
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(25*time.Minute), end)
+
+	c.Set(end)
+	_, err = s.Handle(session.Timeout{})
+	require.NoError(t, err)
+	assert.Equal(t, session.Ended, s.State())
+
+	c.Set(end.Add(2 * time.Minute))
+	result, err = s.Handle(session.Complete{})
+	require.NoError(t, err)
+	require.NotNil(t, result.Finished)
+	assert.Equal(t, now, result.Finished.Start)
+	assert.Equal(t, end, result.Finished.End)
+	assert.Equal(t, []string{"b", "c"}, taskIDs(result.Finished.Tasks))
+	assert.Equal(t, []string{"a", "b"}, taskIDs(s.Current().Tasks))
+	assert.Equal(t, []string{"c"}, taskIDs(result.Archived))
+	assert.Len(t, s.Completed(), 4)
+
+	// the fourth pomodoro of the day earns a long break
+	assert.Equal(t, session.LongBreak, s.State())
+	breakEnd, ok := s.End()
+	assert.True(t, ok)
+	assert.Equal(t, c.Now().Add(15*time.Minute), breakEnd)
+
+	c.Set(breakEnd)
+	_, err = s.Handle(session.Timeout{})
+	require.NoError(t, err)
+	assert.Equal(t, session.BreakEnded, s.State())
+
+	_, err = s.Handle(session.Start{})
+	require.NoError(t, err)
+	assert.Equal(t, session.Active, s.State())
+}
+
+func TestSession_Cancel(t *testing.T) {
+	c := clock.NewFake(now)
+	s := session.New(config.Default(), c)
+	s.Load(pomo.Pomo{}, nil)
+
+	_, err := s.Handle(session.Start{})
+	require.NoError(t, err)
+
+	c.Set(now.Add(5 * time.Minute))
+	_, err = s.Handle(session.Pause{})
+	require.NoError(t, err)
+
+	c.Set(now.Add(8 * time.Minute))
+	result, err := s.Handle(session.Cancel{
+		Outcome: pomo.Interrupted,
+		Reason:  "fire alarm",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Finished)
+	assert.Equal(t, pomo.Interrupted, result.Finished.Outcome)
+	assert.Equal(t, "fire alarm", result.Finished.Reason)
+	assert.Equal(t, c.Now(), result.Finished.End)
+	assert.Equal(t, 5*time.Minute, result.Finished.Focused())
+
+	assert.Equal(t, session.Idle, s.State())
+	assert.Empty(t, s.Completed())
+	assert.Equal(t, pomo.Pomo{}, s.Current())
+}
+
+func TestSession_PauseResume(t *testing.T) {
+	c := clock.NewFake(now)
+	s := session.New(config.Default(), c)
+	s.Load(pomo.Pomo{}, nil)
+
+	_, err := s.Handle(session.Start{})
+	require.NoError(t, err)
+
+	c.Set(now.Add(10 * time.Minute))
+	_, err = s.Handle(session.Pause{})
+	require.NoError(t, err)
+	assert.True(t, s.Paused())
+	assert.Equal(t, 15*time.Minute, s.Remaining())
+	_, ok := s.End()
+	assert.False(t, ok)
+
+	// time stands still while paused
+	c.Set(now.Add(time.Hour))
+	assert.Equal(t, 15*time.Minute, s.Remaining())
+	_, err = s.Handle(session.Timeout{})
+	assert.ErrorIs(t, err, session.ErrNotAllowed)
+
+	_, err = s.Handle(session.Resume{})
+	require.NoError(t, err)
+	assert.False(t, s.Paused())
+	end, ok := s.End()
+	assert.True(t, ok)
+	assert.Equal(t, c.Now().Add(15*time.Minute), end)
+	assert.Equal(t, session.Active, s.State())
+}
+
+func TestSession_NotAllowed(t *testing.T) {
+	tests := map[string]struct {
+		current pomo.Pomo
+		event   session.Event
+	}{
+		"cancel while idle": {
+			event: session.Cancel{},
+		},
+		"complete while idle": {
+			event: session.Complete{},
+		},
+		"resume while running": {
+			current: pomo.Pomo{
+				Start: now.Add(-time.Minute),
+				End:   now.Add(time.Minute),
+			},
+			event: session.Resume{},
+		},
+		"start while active": {
+			current: pomo.Pomo{
+				Start: now.Add(-time.Minute),
+				End:   now.Add(time.Minute),
+			},
+			event: session.Start{},
+		},
+		"interrupt on a break": {
+			current: pomo.Pomo{
+				Start: now.Add(time.Minute),
+			},
+			event: session.Interrupt{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := session.New(config.Default(), clock.NewFake(now))
+			s.Load(tc.current, nil)
+			before := s.Current()
+
+			result, err := s.Handle(tc.event)
+			assert.ErrorIs(t, err, session.ErrNotAllowed)
+			assert.False(t, result.Changed)
+			assert.Equal(t, before, s.Current())
+		})
+	}
+}
+
+func TestSession_Load(t *testing.T) {
+	ended := pomo.Pomo{
+		Start: now.Add(-40 * time.Minute),
+		End:   now.Add(-15 * time.Minute),
+	}
+
+	tests := map[string]struct {
+		configure   func(*config.Config)
+		current     pomo.Pomo
+		completed   int
+		wantState   session.State
+		wantChanged bool
+		wantEnd     time.Time
+		wantFinish  bool
+	}{
+		"pomodoro ended": {
+			current:   ended,
+			wantState: session.Ended,
+		},
+		"auto-start break that is still running": {
+			configure: func(cfg *config.Config) {
+				cfg.AutoStartBreak = true
+				cfg.BreakDuration = 20 * time.Minute
+			},
+			current:     ended,
+			wantState:   session.Break,
+			wantChanged: true,
+			wantEnd:     now.Add(5 * time.Minute),
+			wantFinish:  true,
+		},
+		"auto-start break that already ended": {
+			configure: func(cfg *config.Config) {
+				cfg.AutoStartBreak = true
+			},
+			current:     ended,
+			wantState:   session.BreakEnded,
+			wantChanged: true,
+			wantFinish:  true,
+		},
+		"auto-start pomodoro that is still running": {
+			configure: func(cfg *config.Config) {
+				cfg.AutoStartPomodoro = true
+			},
+			current: pomo.Pomo{
+				Start: now.Add(-10 * time.Minute),
+			},
+			completed:   1,
+			wantState:   session.Active,
+			wantChanged: true,
+			wantEnd:     now.Add(15 * time.Minute),
+		},
+		"auto-start pomodoro that would have ended": {
+			configure: func(cfg *config.Config) {
+				cfg.AutoStartPomodoro = true
+			},
+			current: pomo.Pomo{
+				Start: now.Add(-30 * time.Minute),
+			},
+			completed: 1,
+			wantState: session.BreakEnded,
+		},
+		"break resumed after midnight": {
+			configure: func(cfg *config.Config) {
+				cfg.AutoStartPomodoro = true
+			},
+			current: pomo.Pomo{
+				Start: now.AddDate(0, 0, -1),
+			},
+			wantState:   session.Idle,
+			wantChanged: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.Default()
+			if tc.configure != nil {
+				tc.configure(&cfg)
+			}
+			s := session.New(cfg, clock.NewFake(now))
+
+			loaded := s.Load(tc.current, completedPomos(tc.completed))
+			result := s.AutoStart()
+			assert.Equal(t, tc.wantState, s.State())
+			assert.Equal(t, tc.wantChanged, loaded.Changed || result.Changed)
+			assert.Equal(t, tc.wantFinish, result.Finished != nil)
+
+			end, ok := s.End()
+			assert.Equal(t, !tc.wantEnd.IsZero(), ok)
+			assert.Equal(t, tc.wantEnd, end)
+
+			// loading the saved result again is stable
+			again := session.New(cfg, clock.NewFake(now))
+			loaded = again.Load(s.Current(), s.Completed())
+			result = again.AutoStart()
+			assert.False(t, loaded.Changed || result.Changed)
+			assert.Equal(t, s.State(), again.State())
+		})
+	}
+}
+
+func taskIDs(tasks []pomo.Task) []string {
+	var ids []string
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
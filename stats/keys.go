@@ -0,0 +1,32 @@
+package stats
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type KeyMap struct {
+	Close key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Close: key.NewBinding(
+			key.WithKeys("esc", "s"),
+			key.WithHelp("esc", "close stats"),
+		),
+	}
+}
+
+func (m KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			m.Close,
+		},
+	}
+}
+
+func (m KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.Close,
+	}
+}
@@ -0,0 +1,221 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/message"
+)
+
+const (
+	cell       = "■"
+	labelWidth = 4
+	cellWidth  = 2
+)
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	clock clock.Clock
+
+	width  int
+	height int
+
+	stats Stats
+}
+
+func New(c clock.Clock) Model {
+	return Model{
+		KeyMap: DefaultKeyMap(),
+		Styles: DefaultStyles(),
+
+		clock: c,
+	}
+}
+
+// Open returns a command to load the pomodoro history.
+func (m Model) Open() tea.Cmd {
+	return message.LoadStats
+}
+
+// SetPomos computes the stats of the given pomodoros against the daily goal.
+func (m *Model) SetPomos(pomos []pomo.Pomo, goal int) {
+	m.stats = Compute(pomos, goal, m.clock.Now())
+}
+
+// Stats returns the stats displayed.
+func (m Model) Stats() Stats {
+	return m.stats
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.Close):
+			cmd = message.CloseStats
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *Model) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+func (m Model) View() string {
+	title := m.Styles.Title.Render("Stats")
+
+	height := max(0, m.height-lipgloss.Height(title))
+	frame := m.Styles.Frame.
+		Width(max(0, m.width-m.Styles.Frame.GetHorizontalBorderSize())).
+		Height(max(0, height-m.Styles.Frame.GetVerticalBorderSize()))
+
+	var body string
+	if m.stats.Total == 0 {
+		body = m.Styles.Empty.Render("No completed pomodoros")
+	} else {
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			m.viewSummary(),
+			"",
+			m.viewWeekdays(),
+			"",
+			m.viewHeatmap(max(0, m.width-m.Styles.Frame.GetHorizontalFrameSize())),
+		)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		frame.Render(body),
+	)
+}
+
+func (m Model) viewSummary() string {
+	s := m.stats
+
+	goal := "any pomodoro"
+	if s.Goal > 0 {
+		goal = pomo.Plural(s.Goal, "pomodoro")
+	}
+
+	rows := [][2]string{
+		{"Daily goal", goal},
+		{"Current streak", pomo.Plural(s.CurrentStreak, "day")},
+		{"Longest streak", pomo.Plural(s.LongestStreak, "day")},
+		{"Best hour", fmt.Sprintf("%02d:00–%02d:00, %s", s.BestHour, (s.BestHour+1)%24, pomo.Plural(s.BestHourPomos, "pomodoro"))},
+		{"Total", fmt.Sprintf("%s since %s", pomo.Plural(s.Total, "pomodoro"), s.First.Format("Jan 2, 2006"))},
+	}
+
+	var lines []string
+	for _, row := range rows {
+		label := m.Styles.Label.Copy().Width(16).Render(row[0])
+		lines = append(lines, label+m.Styles.Value.Render(row[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) viewWeekdays() string {
+	var averages []string
+	for weekday, average := range m.stats.Weekdays {
+		averages = append(averages, fmt.Sprintf("%s %s",
+			m.Styles.Label.Render(time.Weekday(weekday).String()[:3]),
+			m.Styles.Value.Render(fmt.Sprintf("%.1f", average))))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.Styles.Label.Render("Average pomodoros per weekday"),
+		strings.Join(averages, "  "),
+	)
+}
+
+// viewHeatmap renders a calendar of the pomodoros completed each day, with a
+// column for each week up to today and as many weeks as fit in the width.
+func (m Model) viewHeatmap(width int) string {
+	s := m.stats
+	// the month label of the last week may overhang the last column by one
+	weeks := max(1, (width-labelWidth-1)/cellWidth)
+	lastWeek := s.Today.AddDate(0, 0, -int(s.Today.Weekday()))
+	firstWeek := lastWeek.AddDate(0, 0, -7*(weeks-1))
+
+	// month labels above the first week of each month
+	months := []rune(strings.Repeat(" ", labelWidth+weeks*cellWidth+1))
+	next := 0
+	for w := range weeks {
+		week := firstWeek.AddDate(0, 0, 7*w)
+		if w > 0 && week.Month() == week.AddDate(0, 0, -7).Month() {
+			continue
+		}
+		pos := labelWidth + w*cellWidth
+		label := []rune(week.Format("Jan"))
+		if pos < next || pos+len(label) > len(months) {
+			continue
+		}
+		copy(months[pos:], label)
+		next = pos + len(label) + 1
+	}
+
+	target := s.Goal
+	if target <= 0 {
+		for w := range weeks {
+			for d := range 7 {
+				target = max(target, s.Count(firstWeek.AddDate(0, 0, 7*w+d)))
+			}
+		}
+	}
+
+	lines := []string{m.Styles.Label.Render(string(months))}
+	for weekday := range 7 {
+		var label string
+		if weekday%2 == 1 {
+			label = time.Weekday(weekday).String()[:3]
+		}
+
+		var b strings.Builder
+		b.WriteString(m.Styles.Label.Copy().Width(labelWidth).Render(label))
+		for w := range weeks {
+			day := firstWeek.AddDate(0, 0, 7*w+weekday)
+			if day.After(s.Today) {
+				break
+			}
+			b.WriteString(m.level(s.Count(day), target).Render(cell))
+			b.WriteString(" ")
+		}
+		lines = append(lines, b.String())
+	}
+
+	var legend strings.Builder
+	legend.WriteString(m.Styles.Label.Render("Less") + " ")
+	for _, level := range m.Styles.Levels {
+		legend.WriteString(level.Render(cell) + " ")
+	}
+	legend.WriteString(m.Styles.Label.Render("More"))
+	lines = append(lines, "", legend.String())
+
+	return strings.Join(lines, "\n")
+}
+
+// level returns the heatmap style for a day with the given number of
+// pomodoros, relative to the target. Days that hit the target get the top
+// level.
+func (m Model) level(count, target int) lipgloss.Style {
+	levels := m.Styles.Levels
+	top := len(levels) - 1
+	switch {
+	case count <= 0:
+		return levels[0]
+	case count >= target:
+		return levels[top]
+	default:
+		return levels[1+count*(top-1)/target]
+	}
+}
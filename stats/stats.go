@@ -0,0 +1,131 @@
+// Package stats computes streaks, averages and daily totals of completed
+// pomodoros, and provides a view of them with a calendar heatmap.
+package stats
+
+import (
+	"time"
+
+	"github.com/qualidafial/pomo"
+)
+
+// Stats summarizes the completed pomodoros in the history up to today.
+type Stats struct {
+	// Goal is the daily goal the streaks are measured against. Without a
+	// goal, any day with a completed pomodoro counts towards a streak.
+	Goal int
+	// First is the first day with a completed pomodoro, or zero if there are
+	// none.
+	First time.Time
+	// Today is the last day of the stats.
+	Today time.Time
+	// Total is the number of completed pomodoros.
+	Total int
+
+	// CurrentStreak is the number of consecutive days up to today that the
+	// goal was hit. Today only counts once the goal is hit, so the streak is
+	// not broken until the day is over.
+	CurrentStreak int
+	// LongestStreak is the highest number of consecutive days the goal was
+	// hit.
+	LongestStreak int
+
+	// Weekdays holds the average number of pomodoros completed on each day of
+	// the week, indexed by time.Weekday.
+	Weekdays [7]float64
+
+	// BestHour is the hour of the day in which the most pomodoros were
+	// started, or -1 if there are none.
+	BestHour int
+	// BestHourPomos is the number of pomodoros started in the best hour.
+	BestHourPomos int
+
+	days map[string]int
+}
+
+// Compute computes the stats of the given pomodoros up to the given day. Days
+// are computed in the location of today.
+func Compute(pomos []pomo.Pomo, goal int, today time.Time) Stats {
+	s := Stats{
+		Goal:     goal,
+		Today:    startOfDay(today),
+		BestHour: -1,
+		days:     make(map[string]int),
+	}
+
+	var hours [24]int
+	for _, p := range pomos {
+		if !p.Completed() {
+			continue
+		}
+		start := p.Start.In(s.Today.Location())
+		day := startOfDay(start)
+		if day.After(s.Today) {
+			continue
+		}
+		if s.First.IsZero() || day.Before(s.First) {
+			s.First = day
+		}
+		s.days[dayKey(day)]++
+		s.Total++
+		hours[start.Hour()]++
+	}
+
+	for hour, n := range hours {
+		if n > s.BestHourPomos {
+			s.BestHour = hour
+			s.BestHourPomos = n
+		}
+	}
+
+	if s.First.IsZero() {
+		return s
+	}
+
+	var occurrences [7]int
+	var sums [7]int
+	var streak int
+	for day := s.First; !day.After(s.Today); day = day.AddDate(0, 0, 1) {
+		count := s.Count(day)
+		occurrences[day.Weekday()]++
+		sums[day.Weekday()] += count
+
+		if s.hit(count) {
+			streak++
+			s.LongestStreak = max(s.LongestStreak, streak)
+		} else if !day.Equal(s.Today) {
+			streak = 0
+		}
+	}
+	s.CurrentStreak = streak
+
+	for weekday := range s.Weekdays {
+		if occurrences[weekday] > 0 {
+			s.Weekdays[weekday] = float64(sums[weekday]) / float64(occurrences[weekday])
+		}
+	}
+
+	return s
+}
+
+// Count returns the number of pomodoros completed on the given day.
+func (s Stats) Count(day time.Time) int {
+	return s.days[dayKey(day)]
+}
+
+// Hit returns whether the goal was hit on the given day.
+func (s Stats) Hit(day time.Time) bool {
+	return s.hit(s.Count(day))
+}
+
+func (s Stats) hit(count int) bool {
+	return count >= max(1, s.Goal)
+}
+
+func dayKey(day time.Time) string {
+	return day.Format(time.DateOnly)
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,126 @@
+package stats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+// today is a Tuesday
+var today = time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+// pomos returns n completed pomodoros on the given day, starting at the given
+// hour, one per hour.
+func pomos(day time.Time, hour, n int) []pomo.Pomo {
+	var pomos []pomo.Pomo
+	for i := range n {
+		start := day.Add(time.Duration(hour+i) * time.Hour)
+		pomos = append(pomos, pomo.Pomo{
+			Start: start,
+			End:   start.Add(25 * time.Minute),
+		})
+	}
+	return pomos
+}
+
+func history(days map[int]int) []pomo.Pomo {
+	var history []pomo.Pomo
+	for daysAgo, n := range days {
+		history = append(history, pomos(today.AddDate(0, 0, -daysAgo), 9, n)...)
+	}
+	return history
+}
+
+func TestCompute_Streaks(t *testing.T) {
+	tests := map[string]struct {
+		days        map[int]int
+		goal        int
+		wantCurrent int
+		wantLongest int
+	}{
+		"no history": {
+			goal: 4,
+		},
+		"goal hit today": {
+			days:        map[int]int{0: 4, 1: 5, 2: 4},
+			goal:        4,
+			wantCurrent: 3,
+			wantLongest: 3,
+		},
+		"today still in progress": {
+			days:        map[int]int{0: 2, 1: 4, 2: 4},
+			goal:        4,
+			wantCurrent: 2,
+			wantLongest: 2,
+		},
+		"streak broken yesterday": {
+			days:        map[int]int{0: 4, 1: 3, 2: 4, 3: 4, 4: 4},
+			goal:        4,
+			wantCurrent: 1,
+			wantLongest: 3,
+		},
+		"day without pomodoros breaks the streak": {
+			days:        map[int]int{1: 4, 3: 4, 4: 4},
+			goal:        4,
+			wantCurrent: 1,
+			wantLongest: 2,
+		},
+		"no goal counts any pomodoro": {
+			days:        map[int]int{0: 1, 1: 1, 2: 3},
+			wantCurrent: 3,
+			wantLongest: 3,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := stats.Compute(history(tc.days), tc.goal, today.Add(15*time.Hour))
+			assert.Equal(t, tc.wantCurrent, s.CurrentStreak)
+			assert.Equal(t, tc.wantLongest, s.LongestStreak)
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	var history []pomo.Pomo
+	// two Tuesdays and the Monday in between
+	history = append(history, pomos(today.AddDate(0, 0, -7), 9, 2)...)
+	history = append(history, pomos(today.AddDate(0, 0, -1), 14, 3)...)
+	history = append(history, pomos(today, 9, 4)...)
+	history = append(history, pomo.Pomo{
+		Start:   today.Add(9 * time.Hour),
+		End:     today.Add(9*time.Hour + 10*time.Minute),
+		Outcome: pomo.Cancelled,
+	})
+
+	s := stats.Compute(history, 4, today.Add(18*time.Hour))
+	assert.Equal(t, 9, s.Total)
+	assert.Equal(t, today.AddDate(0, 0, -7), s.First)
+	assert.Equal(t, 4, s.Count(today))
+	assert.True(t, s.Hit(today))
+	assert.False(t, s.Hit(today.AddDate(0, 0, -1)))
+
+	assert.Equal(t, 3.0, s.Weekdays[time.Tuesday])
+	assert.Equal(t, 3.0, s.Weekdays[time.Monday])
+	assert.Equal(t, 0.0, s.Weekdays[time.Sunday])
+
+	// cancelled pomodoros don't count towards the best hour
+	assert.Equal(t, 9, s.BestHour)
+	assert.Equal(t, 2, s.BestHourPomos)
+}
+
+func TestModel_View(t *testing.T) {
+	m := stats.New(clock.NewFake(today.Add(18 * time.Hour)))
+	m.SetSize(80, 30)
+	assert.Contains(t, m.View(), "No completed pomodoros")
+
+	m.SetPomos(history(map[int]int{0: 4, 1: 4, 30: 2}), 4)
+	view := m.View()
+	assert.Contains(t, view, "2 days")
+	assert.Contains(t, view, "Feb")
+	assert.Contains(t, view, "Mar")
+	assert.Contains(t, view, "Less")
+}
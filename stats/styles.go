@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/qualidafial/pomo/color"
+)
+
+type Styles struct {
+	Title lipgloss.Style
+	Frame lipgloss.Style
+	Empty lipgloss.Style
+	Label lipgloss.Style
+	Value lipgloss.Style
+
+	// Levels are the heatmap cell styles, from days without pomodoros to days
+	// that hit the goal.
+	Levels []lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	levels := []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(color.ANSI256Grayscale(0.2)),
+	}
+	for _, green := range []float64{0.4, 0.6, 0.8, 1} {
+		levels = append(levels, lipgloss.NewStyle().
+			Foreground(color.ANSI256ColorCube(0, green, 0)))
+	}
+
+	return Styles{
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Padding(0, 1).
+			Background(lipgloss.Color("62")).
+			Foreground(lipgloss.Color("230")),
+		Frame: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")).
+			Padding(0, 1),
+		Empty: lipgloss.NewStyle().
+			Foreground(color.Gray),
+		Label: lipgloss.NewStyle().
+			Foreground(color.Gray),
+		Value: lipgloss.NewStyle().
+			Bold(true),
+
+		Levels: levels,
+	}
+}
@@ -0,0 +1,15 @@
+package store
+
+import (
+	"fmt"
+)
+
+// Actuals returns the number of completed pomodoros in the history that each
+// task was worked on, by task ID.
+func (s *Store) Actuals() (map[string]int, error) {
+	pomos, err := s.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing pomodoros: %w", err)
+	}
+	return countActuals(pomos), nil
+}
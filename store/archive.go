@@ -0,0 +1,75 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qualidafial/pomo"
+)
+
+const archiveKey = "archive"
+
+// ArchiveTasks adds the given tasks to the archive of done tasks, recording
+// when they were archived. Each task is kept in its own file named by its ID.
+func (s *Store) ArchiveTasks(at time.Time, tasks []pomo.Task) error {
+	err := checkTaskIDs(tasks)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		archived := pomo.ArchivedTask{
+			Task:       task,
+			ArchivedAt: at,
+		}
+		err := s.write(filepath.Join(archiveKey, task.ID), archived)
+		if err != nil {
+			return fmt.Errorf("archiving task %q: %w", task.Name, err)
+		}
+	}
+	return nil
+}
+
+// ListArchive returns the archived tasks, most recently archived first.
+func (s *Store) ListArchive() ([]pomo.ArchivedTask, error) {
+	entries, err := os.ReadDir(filepath.Join(s.path, archiveKey))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading archive directory: %w", err)
+	}
+
+	var tasks []pomo.ArchivedTask
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		var task pomo.ArchivedTask
+		err = s.read(filepath.Join(archiveKey, name), &task)
+		if err != nil {
+			return tasks, fmt.Errorf("reading archived task %s: %w", name, err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	slices.SortStableFunc(tasks, func(a, b pomo.ArchivedTask) int {
+		return b.ArchivedAt.Compare(a.ArchivedAt)
+	})
+	return tasks, nil
+}
+
+// Unarchive removes the task with the given ID from the archive.
+func (s *Store) Unarchive(id string) error {
+	err := s.Delete(filepath.Join(archiveKey, id))
+	if err != nil {
+		return fmt.Errorf("removing task %s from archive: %w", id, err)
+	}
+	return nil
+}
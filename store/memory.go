@@ -0,0 +1,231 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
+)
+
+// Memory is a Storage that keeps everything in memory, e.g. for tests. Like
+// Store, pomodoros in the history and snapshots are keyed by the second, so
+// saving another at the same second replaces the first.
+type Memory struct {
+	clock clock.Clock
+
+	mu        sync.Mutex
+	current   *pomo.Pomo
+	snapshots map[time.Time]pomo.Pomo
+	history   map[time.Time]pomo.Pomo
+	archive   map[string]pomo.ArchivedTask
+	plans     map[string]pomo.Plan
+}
+
+// NewMemory returns an empty in-memory storage. The clock is used to
+// timestamp snapshots of the current pomodoro.
+func NewMemory(c clock.Clock) *Memory {
+	return &Memory{
+		clock:     c,
+		snapshots: make(map[time.Time]pomo.Pomo),
+		history:   make(map[time.Time]pomo.Pomo),
+		archive:   make(map[string]pomo.ArchivedTask),
+		plans:     make(map[string]pomo.Plan),
+	}
+}
+
+func (m *Memory) GetCurrent() (pomo.Pomo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return pomo.Pomo{}, nil
+	}
+	return clonePomo(*m.current), nil
+}
+
+func (m *Memory) SaveCurrent(p pomo.Pomo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p = clonePomo(p)
+	m.snapshots[timeKey(m.clock.Now())] = p
+	m.current = &p
+	return nil
+}
+
+func (m *Memory) ClearCurrent() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.current = nil
+	return nil
+}
+
+func (m *Memory) Snapshots() ([]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var times []time.Time
+	for t := range m.snapshots {
+		times = append(times, t)
+	}
+	slices.SortFunc(times, func(a, b time.Time) int {
+		return b.Compare(a)
+	})
+	return times, nil
+}
+
+func (m *Memory) GetSnapshot(t time.Time) (pomo.Pomo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.snapshots[timeKey(t)]
+	if !ok {
+		return pomo.Pomo{}, fmt.Errorf("reading snapshot %s: %w", t.Local().Format(time.DateTime), os.ErrNotExist)
+	}
+	return clonePomo(p), nil
+}
+
+func (m *Memory) SavePomo(p pomo.Pomo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.history[timeKey(p.End)] = clonePomo(p)
+	return nil
+}
+
+func (m *Memory) List(fromTo ...time.Time) ([]pomo.Pomo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ends []time.Time
+	for end := range m.history {
+		if len(fromTo) > 0 && end.Before(timeKey(fromTo[0])) {
+			continue
+		}
+		if len(fromTo) > 1 && !end.Before(timeKey(fromTo[1])) {
+			continue
+		}
+		ends = append(ends, end)
+	}
+	slices.SortFunc(ends, time.Time.Compare)
+
+	var pomos []pomo.Pomo
+	for _, end := range ends {
+		pomos = append(pomos, clonePomo(m.history[end]))
+	}
+	return pomos, nil
+}
+
+func (m *Memory) Actuals() (map[string]int, error) {
+	pomos, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	return countActuals(pomos), nil
+}
+
+func (m *Memory) ArchiveTasks(at time.Time, tasks []pomo.Task) error {
+	err := checkTaskIDs(tasks)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, task := range tasks {
+		m.archive[task.ID] = pomo.ArchivedTask{
+			Task:       cloneTask(task),
+			ArchivedAt: at,
+		}
+	}
+	return nil
+}
+
+func (m *Memory) ListArchive() ([]pomo.ArchivedTask, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var tasks []pomo.ArchivedTask
+	for _, task := range m.archive {
+		task.Task = cloneTask(task.Task)
+		tasks = append(tasks, task)
+	}
+	// ties are broken by ID, like the file names in Store
+	slices.SortFunc(tasks, func(a, b pomo.ArchivedTask) int {
+		if c := b.ArchivedAt.Compare(a.ArchivedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return tasks, nil
+}
+
+func (m *Memory) Unarchive(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.archive[id]; !ok {
+		return fmt.Errorf("removing task %s from archive: %w", id, os.ErrNotExist)
+	}
+	delete(m.archive, id)
+	return nil
+}
+
+func (m *Memory) GetPlan(day time.Time) (pomo.Plan, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.plans[day.Format(time.DateOnly)]
+	if !ok {
+		return pomo.Plan{}, false, nil
+	}
+	p.Tasks = cloneTasks(p.Tasks)
+	return p, true, nil
+}
+
+func (m *Memory) SavePlan(p pomo.Plan) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p.Tasks = cloneTasks(p.Tasks)
+	m.plans[p.Day.Format(time.DateOnly)] = p
+	return nil
+}
+
+// timeKey returns the time truncated to the second in UTC, matching the
+// resolution of the keys of Store.
+func timeKey(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+// clonePomo copies the pomodoro, so callers can't modify what is stored.
+func clonePomo(p pomo.Pomo) pomo.Pomo {
+	p.Pauses = slices.Clone(p.Pauses)
+	p.Interruptions = slices.Clone(p.Interruptions)
+	p.Tasks = cloneTasks(p.Tasks)
+	return p
+}
+
+func cloneTasks(tasks []pomo.Task) []pomo.Task {
+	if tasks == nil {
+		return nil
+	}
+	clones := make([]pomo.Task, len(tasks))
+	for i, task := range tasks {
+		clones[i] = cloneTask(task)
+	}
+	return clones
+}
+
+func cloneTask(task pomo.Task) pomo.Task {
+	task.Tags = slices.Clone(task.Tags)
+	task.Checklist = slices.Clone(task.Checklist)
+	return task
+}
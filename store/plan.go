@@ -0,0 +1,68 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/qualidafial/pomo"
+)
+
+const planKey = "plan"
+
+// GetPlan returns the plan for the given day, and whether the day was
+// planned.
+func (s *Store) GetPlan(day time.Time) (pomo.Plan, bool, error) {
+	var p pomo.Plan
+	err := s.read(s.planKey(day), &p)
+	if errors.Is(err, os.ErrNotExist) {
+		return pomo.Plan{}, false, nil
+	}
+	if err != nil {
+		return pomo.Plan{}, false, fmt.Errorf("reading plan: %w", err)
+	}
+	return p, true, nil
+}
+
+// SavePlan saves the plan for its day, replacing any earlier plan.
+func (s *Store) SavePlan(p pomo.Plan) error {
+	err := s.write(s.planKey(p.Day), p)
+	if err != nil {
+		return fmt.Errorf("saving plan: %w", err)
+	}
+	return nil
+}
+
+// ListPlans returns all saved plans, by day.
+func (s *Store) ListPlans() ([]pomo.Plan, error) {
+	entries, err := os.ReadDir(filepath.Join(s.path, planKey))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading plan directory: %w", err)
+	}
+
+	var plans []pomo.Plan
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		var p pomo.Plan
+		err = s.read(filepath.Join(planKey, name), &p)
+		if err != nil {
+			return plans, fmt.Errorf("reading plan %s: %w", name, err)
+		}
+		plans = append(plans, p)
+	}
+	return plans, nil
+}
+
+func (s *Store) planKey(day time.Time) string {
+	return filepath.Join(planKey, day.Format(time.DateOnly))
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/qualidafial/pomo/clock"
+)
+
+// pruneInterval is how often AutoPrune prunes snapshots.
+const pruneInterval = time.Hour
+
+// Retention is the policy for pruning snapshots of the current pomodoro. A
+// snapshot is kept if any of the rules keeps it.
+type Retention struct {
+	// Recent is the number of most recent snapshots kept. The newest
+	// snapshot is always kept.
+	Recent int
+	// Hourly is the number of hours, among those with snapshots, for which
+	// the newest snapshot in the hour is kept.
+	Hourly int
+	// Daily is the number of days, among those with snapshots, for which the
+	// newest snapshot of the day is kept.
+	Daily int
+}
+
+func DefaultRetention() Retention {
+	return Retention{
+		Recent: 100,
+		Hourly: 48,
+		Daily:  90,
+	}
+}
+
+// Pruner is a Storage that prunes snapshots of the current pomodoro
+// according to a retention policy.
+type Pruner interface {
+	// SetRetention sets the policy for pruning snapshots of the current
+	// pomodoro.
+	SetRetention(r Retention)
+	// PruneSnapshots deletes the snapshots of the current pomodoro that are
+	// not kept by the retention policy. If dryRun is true, nothing is deleted
+	// but the result reports what would have been.
+	PruneSnapshots(dryRun bool) (PruneResult, error)
+}
+
+var (
+	_ Pruner = (*Store)(nil)
+	_ Pruner = (*SQLite)(nil)
+)
+
+// PruneResult reports the snapshots kept and pruned.
+type PruneResult struct {
+	Kept   int
+	Pruned int
+	// Freed is the total size in bytes of the pruned snapshots.
+	Freed int64
+}
+
+// SetRetention sets the policy for pruning snapshots of the current pomodoro.
+func (s *Store) SetRetention(r Retention) {
+	s.retention = r
+}
+
+// PruneSnapshots deletes the snapshots of the current pomodoro that are not
+// kept by the retention policy. If dryRun is true, nothing is deleted but the
+// result reports what would have been.
+func (s *Store) PruneSnapshots(dryRun bool) (PruneResult, error) {
+	var result PruneResult
+
+	names, err := s.snapshotNames()
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("reading snapshot directory: %w", err)
+	}
+
+	dir := filepath.Join(s.path, currentPomo)
+	keep := s.retention.keep(names)
+	for _, name := range names {
+		if keep[name] {
+			result.Kept++
+			continue
+		}
+
+		path := filepath.Join(dir, name+".yaml")
+		info, err := os.Stat(path)
+		if err != nil {
+			return result, fmt.Errorf("pruning snapshot %s: %w", name, err)
+		}
+		if !dryRun {
+			err = os.Remove(path)
+			if err != nil {
+				return result, fmt.Errorf("pruning snapshot %s: %w", name, err)
+			}
+		}
+		result.Pruned++
+		result.Freed += info.Size()
+	}
+	return result, nil
+}
+
+// keep returns the names of the snapshots to keep out of the given snapshot
+// names, sorted from oldest to newest.
+func (r Retention) keep(names []string) map[string]bool {
+	keep := make(map[string]bool)
+	hours := make(map[string]bool)
+	days := make(map[string]bool)
+
+	for i := len(names) - 1; i >= 0; i-- {
+		name := names[i]
+		if len(names)-i <= max(1, r.Recent) {
+			keep[name] = true
+		}
+
+		t, err := time.ParseInLocation(timeKeyFormat, name, time.UTC)
+		if err != nil {
+			// not a snapshot written by the store, leave it alone
+			keep[name] = true
+			continue
+		}
+		t = t.Local()
+
+		hour := t.Format("2006-01-02T15")
+		if !hours[hour] && len(hours) < r.Hourly {
+			hours[hour] = true
+			keep[name] = true
+		}
+		day := t.Format(time.DateOnly)
+		if !days[day] && len(days) < r.Daily {
+			days[day] = true
+			keep[name] = true
+		}
+	}
+	return keep
+}
+
+// AutoPrune prunes snapshots right away and then periodically, until stop is
+// closed. It is meant to run in the background, so that pruning a large
+// backlog of snapshots doesn't hold up saving. Errors are logged.
+func AutoPrune(p Pruner, c clock.Clock, stop <-chan struct{}) {
+	for {
+		result, err := p.PruneSnapshots(false)
+		if err != nil {
+			log.Error("pruning snapshots", "err", err)
+		} else if result.Pruned > 0 {
+			log.Info("pruned snapshots", "pruned", result.Pruned, "kept", result.Kept)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-c.After(pruneInterval):
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qualidafial/pomo"
+)
+
+// Snapshots returns the times of the snapshots of the current pomodoro, newest
+// first.
+func (s *Store) Snapshots() ([]time.Time, error) {
+	names, err := s.snapshotNames()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot directory: %w", err)
+	}
+
+	var times []time.Time
+	for i := len(names) - 1; i >= 0; i-- {
+		t, err := time.ParseInLocation(timeKeyFormat, names[i], time.UTC)
+		if err != nil {
+			// not a snapshot written by the store
+			continue
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+// GetSnapshot returns the snapshot of the current pomodoro taken at the given
+// time, as returned by Snapshots.
+func (s *Store) GetSnapshot(t time.Time) (pomo.Pomo, error) {
+	p, err := s.Read(filepath.Join(currentPomo, s.formatTimeKey(t)))
+	if err != nil {
+		return pomo.Pomo{}, fmt.Errorf("reading snapshot %s: %w", t.Local().Format(time.DateTime), err)
+	}
+	return p, nil
+}
+
+// snapshotNames returns the names of the snapshot files, without extension,
+// from oldest to newest.
+func (s *Store) snapshotNames() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.path, currentPomo))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
@@ -0,0 +1,556 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
+	"gopkg.in/yaml.v3"
+	_ "modernc.org/sqlite"
+)
+
+// schema creates the tables of the database. Each record is kept as the same
+// YAML document Store writes to its files, alongside the columns it is
+// queried by. Times are stored as Unix seconds, at the resolution of the keys
+// of Store. Pomodoros are indexed by end time for List, and by task for
+// ListByTask and Actuals.
+const schema = `
+CREATE TABLE IF NOT EXISTS current (
+	id   INTEGER PRIMARY KEY CHECK (id = 0),
+	data TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS snapshots (
+	time INTEGER PRIMARY KEY,
+	data TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS pomos (
+	end_time INTEGER PRIMARY KEY,
+	data     TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS pomo_tasks (
+	end_time  INTEGER NOT NULL,
+	task_id   TEXT NOT NULL,
+	completed INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS pomo_tasks_end_time ON pomo_tasks (end_time);
+CREATE INDEX IF NOT EXISTS pomo_tasks_task_id ON pomo_tasks (task_id, completed);
+
+CREATE TABLE IF NOT EXISTS archive (
+	task_id     TEXT PRIMARY KEY,
+	archived_at INTEGER NOT NULL,
+	data        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS archive_archived_at ON archive (archived_at);
+
+CREATE TABLE IF NOT EXISTS plans (
+	day  TEXT PRIMARY KEY,
+	data TEXT NOT NULL
+);
+`
+
+// SQLite is a Storage that keeps everything in a SQLite database, so the
+// history can be queried by time range without reading every pomodoro.
+type SQLite struct {
+	db    *sql.DB
+	clock clock.Clock
+
+	retention Retention
+}
+
+// OpenSQLite opens the SQLite database at the given path, creating it if
+// needed. The clock is used to timestamp snapshots of the current pomodoro.
+func OpenSQLite(path string, c clock.Clock) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	_, err = db.Exec(schema)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("creating database schema: %w", err), db.Close())
+	}
+
+	return &SQLite{
+		db:        db,
+		clock:     c,
+		retention: DefaultRetention(),
+	}, nil
+}
+
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLite) GetCurrent() (pomo.Pomo, error) {
+	var p pomo.Pomo
+	_, err := s.get(&p, `SELECT data FROM current WHERE id = 0`)
+	if err != nil {
+		return pomo.Pomo{}, fmt.Errorf("reading current pomo: %w", err)
+	}
+	return p, nil
+}
+
+func (s *SQLite) SaveCurrent(p pomo.Pomo) error {
+	err := s.tx(func(tx *sql.Tx) error {
+		err := insertSnapshot(tx, s.clock.Now(), p)
+		if err != nil {
+			return err
+		}
+		return putCurrent(tx, p)
+	})
+	if err != nil {
+		return fmt.Errorf("saving current pomo: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLite) ClearCurrent() error {
+	_, err := s.db.Exec(`DELETE FROM current`)
+	if err != nil {
+		return fmt.Errorf("clearing current pomo: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLite) Snapshots() ([]time.Time, error) {
+	rows, err := s.db.Query(`SELECT time FROM snapshots ORDER BY time DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var times []time.Time
+	for rows.Next() {
+		var t int64
+		err = rows.Scan(&t)
+		if err != nil {
+			return nil, fmt.Errorf("listing snapshots: %w", err)
+		}
+		times = append(times, time.Unix(t, 0).UTC())
+	}
+	return times, rows.Err()
+}
+
+func (s *SQLite) GetSnapshot(t time.Time) (pomo.Pomo, error) {
+	var p pomo.Pomo
+	ok, err := s.get(&p, `SELECT data FROM snapshots WHERE time = ?`, timeKey(t).Unix())
+	if err == nil && !ok {
+		err = os.ErrNotExist
+	}
+	if err != nil {
+		return pomo.Pomo{}, fmt.Errorf("reading snapshot %s: %w", t.Local().Format(time.DateTime), err)
+	}
+	return p, nil
+}
+
+func (s *SQLite) SavePomo(p pomo.Pomo) error {
+	err := s.tx(func(tx *sql.Tx) error {
+		return insertPomo(tx, p)
+	})
+	if err != nil {
+		return fmt.Errorf("saving %s pomo: %w", p.Outcome, err)
+	}
+	return nil
+}
+
+func (s *SQLite) List(fromTo ...time.Time) ([]pomo.Pomo, error) {
+	from, to := int64(math.MinInt64), int64(math.MaxInt64)
+	if len(fromTo) > 0 {
+		from = timeKey(fromTo[0]).Unix()
+	}
+	if len(fromTo) > 1 {
+		to = timeKey(fromTo[1]).Unix()
+	}
+
+	return s.listPomos(`SELECT data FROM pomos WHERE end_time >= ? AND end_time < ? ORDER BY end_time`, from, to)
+}
+
+// ListByTask returns the pomodoros in the history the task with the given ID
+// was worked on, in the order they ended.
+func (s *SQLite) ListByTask(id string) ([]pomo.Pomo, error) {
+	return s.listPomos(`SELECT data FROM pomos WHERE end_time IN
+		(SELECT end_time FROM pomo_tasks WHERE task_id = ?) ORDER BY end_time`, id)
+}
+
+// listPomos returns the pomodoros selected by the query.
+func (s *SQLite) listPomos(query string, args ...any) ([]pomo.Pomo, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing pomos: %w", err)
+	}
+	defer rows.Close()
+
+	var pomos []pomo.Pomo
+	for rows.Next() {
+		var p pomo.Pomo
+		err = scanYAML(rows, &p)
+		if err != nil {
+			return pomos, fmt.Errorf("listing pomos: %w", err)
+		}
+		pomos = append(pomos, p)
+	}
+	return pomos, rows.Err()
+}
+
+func (s *SQLite) Actuals() (map[string]int, error) {
+	rows, err := s.db.Query(`SELECT task_id, COUNT(*) FROM pomo_tasks WHERE completed GROUP BY task_id`)
+	if err != nil {
+		return nil, fmt.Errorf("counting actuals: %w", err)
+	}
+	defer rows.Close()
+
+	actuals := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var count int
+		err = rows.Scan(&id, &count)
+		if err != nil {
+			return nil, fmt.Errorf("counting actuals: %w", err)
+		}
+		actuals[id] = count
+	}
+	return actuals, rows.Err()
+}
+
+func (s *SQLite) ArchiveTasks(at time.Time, tasks []pomo.Task) error {
+	err := checkTaskIDs(tasks)
+	if err != nil {
+		return err
+	}
+	return s.tx(func(tx *sql.Tx) error {
+		for _, task := range tasks {
+			err := insertArchived(tx, pomo.ArchivedTask{
+				Task:       task,
+				ArchivedAt: at,
+			})
+			if err != nil {
+				return fmt.Errorf("archiving task %q: %w", task.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *SQLite) ListArchive() ([]pomo.ArchivedTask, error) {
+	rows, err := s.db.Query(`SELECT data FROM archive ORDER BY archived_at DESC, task_id`)
+	if err != nil {
+		return nil, fmt.Errorf("listing archive: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []pomo.ArchivedTask
+	for rows.Next() {
+		var task pomo.ArchivedTask
+		err = scanYAML(rows, &task)
+		if err != nil {
+			return tasks, fmt.Errorf("listing archive: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+func (s *SQLite) Unarchive(id string) error {
+	result, err := s.db.Exec(`DELETE FROM archive WHERE task_id = ?`, id)
+	if err == nil {
+		var n int64
+		n, err = result.RowsAffected()
+		if err == nil && n == 0 {
+			err = os.ErrNotExist
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("removing task %s from archive: %w", id, err)
+	}
+	return nil
+}
+
+func (s *SQLite) GetPlan(day time.Time) (pomo.Plan, bool, error) {
+	var p pomo.Plan
+	ok, err := s.get(&p, `SELECT data FROM plans WHERE day = ?`, day.Format(time.DateOnly))
+	if err != nil {
+		return pomo.Plan{}, false, fmt.Errorf("reading plan: %w", err)
+	}
+	return p, ok, nil
+}
+
+func (s *SQLite) SavePlan(p pomo.Plan) error {
+	err := insertPlan(s.db, p)
+	if err != nil {
+		return fmt.Errorf("saving plan: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLite) SetRetention(r Retention) {
+	s.retention = r
+}
+
+func (s *SQLite) PruneSnapshots(dryRun bool) (PruneResult, error) {
+	var result PruneResult
+
+	rows, err := s.db.Query(`SELECT time, length(data) FROM snapshots ORDER BY time`)
+	if err != nil {
+		return result, fmt.Errorf("listing snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	times := make(map[string]int64)
+	sizes := make(map[string]int64)
+	for rows.Next() {
+		var t, size int64
+		err = rows.Scan(&t, &size)
+		if err != nil {
+			return result, fmt.Errorf("listing snapshots: %w", err)
+		}
+		name := time.Unix(t, 0).UTC().Format(timeKeyFormat)
+		names = append(names, name)
+		times[name] = t
+		sizes[name] = size
+	}
+	if err = rows.Err(); err != nil {
+		return result, fmt.Errorf("listing snapshots: %w", err)
+	}
+
+	keep := s.retention.keep(names)
+	err = s.tx(func(tx *sql.Tx) error {
+		for _, name := range names {
+			if keep[name] {
+				result.Kept++
+				continue
+			}
+			if !dryRun {
+				_, err := tx.Exec(`DELETE FROM snapshots WHERE time = ?`, times[name])
+				if err != nil {
+					return fmt.Errorf("pruning snapshot %s: %w", name, err)
+				}
+			}
+			result.Pruned++
+			result.Freed += sizes[name]
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Counts is the number of records of each kind in a storage.
+type Counts struct {
+	Pomos     int
+	Snapshots int
+	Archived  int
+	Plans     int
+}
+
+// Counts returns the number of records of each kind in the database.
+func (s *SQLite) Counts() (Counts, error) {
+	return countRecords(s.db)
+}
+
+// countRecords returns the number of records of each kind in the database,
+// either in a transaction or not.
+func countRecords(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}) (Counts, error) {
+	var c Counts
+	err := q.QueryRow(`SELECT
+		(SELECT COUNT(*) FROM pomos),
+		(SELECT COUNT(*) FROM snapshots),
+		(SELECT COUNT(*) FROM archive),
+		(SELECT COUNT(*) FROM plans)`,
+	).Scan(&c.Pomos, &c.Snapshots, &c.Archived, &c.Plans)
+	if err != nil {
+		return c, fmt.Errorf("counting records: %w", err)
+	}
+	return c, nil
+}
+
+// Import replaces the contents of the database with the contents of the YAML
+// store: the current pomodoro and its snapshots, the history, archived tasks
+// and plans. Fails if any record of the store can't be read, or if the
+// records in the database don't match the files of the store, in which case
+// the database is left unchanged.
+func (s *SQLite) Import(src *Store) (Counts, error) {
+	want, err := src.Counts()
+	if err != nil {
+		return Counts{}, err
+	}
+
+	current, err := src.GetCurrent()
+	if err != nil {
+		return Counts{}, err
+	}
+	pomos, err := src.List()
+	if err != nil {
+		return Counts{}, err
+	}
+	archived, err := src.ListArchive()
+	if err != nil {
+		return Counts{}, err
+	}
+	plans, err := src.ListPlans()
+	if err != nil {
+		return Counts{}, err
+	}
+	times, err := src.Snapshots()
+	if err != nil {
+		return Counts{}, err
+	}
+
+	err = s.tx(func(tx *sql.Tx) error {
+		for _, table := range []string{"current", "snapshots", "pomos", "pomo_tasks", "archive", "plans"} {
+			_, err := tx.Exec(`DELETE FROM ` + table)
+			if err != nil {
+				return fmt.Errorf("clearing %s: %w", table, err)
+			}
+		}
+
+		err := putCurrent(tx, current)
+		if err != nil {
+			return err
+		}
+		for _, t := range times {
+			snapshot, err := src.GetSnapshot(t)
+			if err != nil {
+				return err
+			}
+			err = insertSnapshot(tx, t, snapshot)
+			if err != nil {
+				return err
+			}
+		}
+		for _, p := range pomos {
+			err = insertPomo(tx, p)
+			if err != nil {
+				return err
+			}
+		}
+		for _, task := range archived {
+			err = insertArchived(tx, task)
+			if err != nil {
+				return err
+			}
+		}
+		for _, plan := range plans {
+			err = insertPlan(tx, plan)
+			if err != nil {
+				return err
+			}
+		}
+
+		got, err := countRecords(tx)
+		if err != nil {
+			return err
+		}
+		if got != want {
+			return fmt.Errorf("verifying import: found %+v in the store, but imported %+v", want, got)
+		}
+		return nil
+	})
+	if err != nil {
+		return Counts{}, fmt.Errorf("importing: %w", err)
+	}
+	return want, nil
+}
+
+// tx runs fn in a transaction, which is committed if fn succeeds and rolled
+// back otherwise.
+func (s *SQLite) tx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	err = fn(tx)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// get decodes the YAML document selected by the query into v. Returns false
+// if the query selects no rows.
+func (s *SQLite) get(v any, query string, args ...any) (bool, error) {
+	err := scanYAML(s.db.QueryRow(query, args...), v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// execer executes statements, either in a transaction or not.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func putCurrent(tx execer, p pomo.Pomo) error {
+	return execYAML(tx, `INSERT OR REPLACE INTO current (id, data) VALUES (0, ?)`, p)
+}
+
+func insertSnapshot(tx execer, t time.Time, p pomo.Pomo) error {
+	return execYAML(tx, `INSERT OR REPLACE INTO snapshots (data, time) VALUES (?, ?)`, p, timeKey(t).Unix())
+}
+
+func insertPomo(tx execer, p pomo.Pomo) error {
+	end := timeKey(p.End).Unix()
+	err := execYAML(tx, `INSERT OR REPLACE INTO pomos (data, end_time) VALUES (?, ?)`, p, end)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM pomo_tasks WHERE end_time = ?`, end)
+	if err != nil {
+		return err
+	}
+	for _, task := range p.Tasks {
+		if task.ID == "" {
+			continue
+		}
+		_, err = tx.Exec(`INSERT INTO pomo_tasks (end_time, task_id, completed) VALUES (?, ?, ?)`, end, task.ID, p.Completed())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertArchived(tx execer, task pomo.ArchivedTask) error {
+	return execYAML(tx, `INSERT OR REPLACE INTO archive (data, task_id, archived_at) VALUES (?, ?, ?)`,
+		task, task.ID, task.ArchivedAt.Unix())
+}
+
+func insertPlan(tx execer, p pomo.Plan) error {
+	return execYAML(tx, `INSERT OR REPLACE INTO plans (data, day) VALUES (?, ?)`, p, p.Day.Format(time.DateOnly))
+}
+
+// execYAML executes the statement with v encoded as a YAML document for the
+// first parameter, followed by the given arguments.
+func execYAML(tx execer, query string, v any, args ...any) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding: %w", err)
+	}
+	_, err = tx.Exec(query, append([]any{string(data)}, args...)...)
+	return err
+}
+
+// scanYAML decodes the YAML document in the single column of the row into v.
+func scanYAML(row interface{ Scan(dest ...any) error }, v any) error {
+	var data string
+	err := row.Scan(&data)
+	if err != nil {
+		return err
+	}
+	err = yaml.Unmarshal([]byte(data), v)
+	if err != nil {
+		return fmt.Errorf("decoding: %w", err)
+	}
+	return nil
+}
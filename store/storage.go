@@ -0,0 +1,106 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qualidafial/pomo"
+)
+
+// Storage stores the current pomodoro and its snapshots, the history of
+// finished pomodoros, archived tasks and daily plans. Store keeps them in
+// YAML files in a directory, and Memory keeps them in memory.
+type Storage interface {
+	// GetCurrent returns the current pomodoro, or a zero pomodoro if none was
+	// saved.
+	GetCurrent() (pomo.Pomo, error)
+	// SaveCurrent saves the current pomodoro, along with a snapshot taken at
+	// the current time.
+	SaveCurrent(p pomo.Pomo) error
+	// ClearCurrent removes the current pomodoro. Snapshots are kept.
+	ClearCurrent() error
+	// Snapshots returns the times of the snapshots of the current pomodoro,
+	// newest first.
+	Snapshots() ([]time.Time, error)
+	// GetSnapshot returns the snapshot of the current pomodoro taken at the
+	// given time, as returned by Snapshots.
+	GetSnapshot(t time.Time) (pomo.Pomo, error)
+
+	// SavePomo adds a finished pomodoro to the history.
+	SavePomo(p pomo.Pomo) error
+	// List returns the pomodoros in the history in the order they ended,
+	// optionally limited to those that ended from the first given time,
+	// inclusive, to the second, exclusive.
+	List(fromTo ...time.Time) ([]pomo.Pomo, error)
+	// Actuals returns the number of completed pomodoros in the history that
+	// each task was worked on, by task ID.
+	Actuals() (map[string]int, error)
+
+	// ArchiveTasks adds the given tasks to the archive of done tasks,
+	// recording when they were archived. Fails without archiving anything if
+	// a task has no ID, as it could never be restored.
+	ArchiveTasks(at time.Time, tasks []pomo.Task) error
+	// ListArchive returns the archived tasks, most recently archived first.
+	ListArchive() ([]pomo.ArchivedTask, error)
+	// Unarchive removes the task with the given ID from the archive.
+	Unarchive(id string) error
+
+	// GetPlan returns the plan for the given day, and whether the day was
+	// planned.
+	GetPlan(day time.Time) (pomo.Plan, bool, error)
+	// SavePlan saves the plan for its day, replacing any earlier plan.
+	SavePlan(p pomo.Plan) error
+}
+
+var (
+	_ Storage = (*Store)(nil)
+	_ Storage = (*Memory)(nil)
+	_ Storage = (*SQLite)(nil)
+)
+
+// Backend selects the implementation of Storage used by the app.
+type Backend string
+
+const (
+	// BackendYAML keeps one YAML file per pomodoro in a directory, using
+	// Store.
+	BackendYAML Backend = "yaml"
+	// BackendSQLite keeps everything in a SQLite database, using SQLite.
+	BackendSQLite Backend = "sqlite"
+)
+
+func ParseBackend(s string) (Backend, error) {
+	switch b := Backend(s); b {
+	case BackendYAML, BackendSQLite:
+		return b, nil
+	default:
+		return "", fmt.Errorf("invalid storage backend %q: expected %s or %s", s, BackendYAML, BackendSQLite)
+	}
+}
+
+// countActuals counts the completed pomodoros each task was worked on, by
+// task ID.
+func countActuals(pomos []pomo.Pomo) map[string]int {
+	actuals := make(map[string]int)
+	for _, p := range pomos {
+		if !p.Completed() {
+			continue
+		}
+		for _, task := range p.Tasks {
+			if task.ID != "" {
+				actuals[task.ID]++
+			}
+		}
+	}
+	return actuals
+}
+
+// checkTaskIDs returns an error if any of the tasks to archive has no ID.
+func checkTaskIDs(tasks []pomo.Task) error {
+	for _, task := range tasks {
+		if task.ID == "" {
+			return fmt.Errorf("archiving task %q: task has no ID", task.Name)
+		}
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/charmbracelet/log"
 	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
 	"gopkg.in/yaml.v3"
 )
 
@@ -23,7 +24,9 @@ const (
 	timeKeyFormat = "2006-01-02_150405"
 )
 
-func New(path string) (*Store, error) {
+// New opens the store at the given path, creating it if needed. The clock is
+// used to timestamp snapshots of the current pomodoro.
+func New(path string, c clock.Clock) (*Store, error) {
 	storeDir, err := filepath.Abs(path)
 	if err != nil {
 		return nil, fmt.Errorf("getting store directory absolute path: %w", err)
@@ -41,12 +44,17 @@ func New(path string) (*Store, error) {
 	}
 
 	return &Store{
-		path: path,
+		path:      path,
+		clock:     c,
+		retention: DefaultRetention(),
 	}, nil
 }
 
 type Store struct {
-	path string
+	path  string
+	clock clock.Clock
+
+	retention Retention
 }
 
 func (s *Store) ClearCurrent() error {
@@ -57,21 +65,56 @@ func (s *Store) ClearCurrent() error {
 	return nil
 }
 
+// GetCurrent returns the current pomodoro. If the current pomodoro file can't
+// be read, e.g. after a crash, the newest readable snapshot is returned
+// instead.
 func (s *Store) GetCurrent() (pomo.Pomo, error) {
 	p, err := s.Read(currentPomo)
-	if err != nil && !errors.Is(err, os.ErrNotExist) {
-		return p, fmt.Errorf("reading current pomo: %w", err)
+	if errors.Is(err, os.ErrNotExist) {
+		return pomo.Pomo{}, nil
+	}
+	if err == nil {
+		return p, nil
+	}
+
+	snapshot, p, snapshotErr := s.latestSnapshot()
+	if snapshotErr != nil {
+		return pomo.Pomo{}, fmt.Errorf("reading current pomo: %w", errors.Join(err, snapshotErr))
 	}
+	log.Warn("current pomo is unreadable, restored from snapshot", "snapshot", snapshot, "err", err)
 	return p, nil
 }
 
+// SaveCurrent saves the current pomodoro, along with a timestamped snapshot.
+// The snapshot is saved first, so the newest snapshot is always a good copy
+// of the current pomodoro. Old snapshots are pruned separately, see
+// AutoPrune.
 func (s *Store) SaveCurrent(p pomo.Pomo) error {
-	err := s.Save(currentPomo, p)
+	key := filepath.Join(currentPomo, s.formatTimeKey(s.clock.Now()))
+	err := s.Save(key, p)
 	if err != nil {
 		return err
 	}
-	key := filepath.Join(currentPomo, s.formatTimeKey(time.Now()))
-	return s.Save(key, p)
+	return s.Save(currentPomo, p)
+}
+
+// latestSnapshot returns the key and contents of the newest readable snapshot
+// of the current pomodoro.
+func (s *Store) latestSnapshot() (string, pomo.Pomo, error) {
+	names, err := s.snapshotNames()
+	if err != nil {
+		return "", pomo.Pomo{}, fmt.Errorf("reading snapshot directory: %w", err)
+	}
+
+	for i := len(names) - 1; i >= 0; i-- {
+		key := filepath.Join(currentPomo, names[i])
+		p, err := s.Read(key)
+		if err == nil {
+			return key, p, nil
+		}
+		log.Warn("skipping unreadable snapshot", "snapshot", key, "err", err)
+	}
+	return "", pomo.Pomo{}, errors.New("no readable snapshot")
 }
 
 func (s *Store) List(fromTo ...time.Time) ([]pomo.Pomo, error) {
@@ -100,15 +143,8 @@ func (s *Store) SavePomo(p pomo.Pomo) error {
 
 func (s *Store) Read(key string) (pomo.Pomo, error) {
 	var p pomo.Pomo
-
-	path := s.pomoFile(key)
-	f, err := os.Open(path)
-	if err != nil {
-		return p, fmt.Errorf("opening file: %w", err)
-	}
-
-	err = yaml.NewDecoder(f).Decode(&p)
-	return p, errors.Join(err, f.Close())
+	err := s.read(key, &p)
+	return p, err
 }
 
 func (s *Store) Save(key string, p pomo.Pomo) (err error) {
@@ -120,11 +156,31 @@ func (s *Store) Save(key string, p pomo.Pomo) (err error) {
 		}
 	}()
 
+	return s.write(key, p)
+}
+
+// read decodes the YAML file with the given key into v.
+func (s *Store) read(key string, v any) error {
 	path := s.pomoFile(key)
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+
+	err = yaml.NewDecoder(f).Decode(v)
+	return errors.Join(err, f.Close())
+}
 
-	data, err := yaml.Marshal(p)
+// write encodes v to the YAML file with the given key. The file is replaced
+// atomically: v is written to a temporary file which is synced to disk, then
+// renamed over the file, so a crash or a full disk never leaves a partially
+// written file behind.
+func (s *Store) write(key string, v any) (err error) {
+	path := s.pomoFile(key)
+
+	data, err := yaml.Marshal(v)
 	if err != nil {
-		return fmt.Errorf("encoding pomodoro to file: %w", err)
+		return fmt.Errorf("encoding to file: %w", err)
 	}
 
 	dir := filepath.Dir(path)
@@ -133,22 +189,53 @@ func (s *Store) Save(key string, p pomo.Pomo) (err error) {
 		return fmt.Errorf("creating parent directory for file: %w", err)
 	}
 
-	f, err := os.Create(path)
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
 	if err != nil {
-		return fmt.Errorf("creating pomodoro file: %w", err)
+		return fmt.Errorf("creating temporary file: %w", err)
 	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
 
 	_, err = f.Write(data)
 	if err != nil {
-		return fmt.Errorf("writing pomodoro to file: %w", err)
+		return errors.Join(fmt.Errorf("writing file: %w", err), f.Close())
+	}
+
+	err = f.Sync()
+	if err != nil {
+		return errors.Join(fmt.Errorf("syncing file: %w", err), f.Close())
 	}
 
-	closeErr := f.Close()
-	if closeErr != nil {
-		closeErr = fmt.Errorf("closing pomodoro file: %w", closeErr)
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("closing file: %w", err)
 	}
 
-	return errors.Join(err, closeErr)
+	err = os.Rename(f.Name(), path)
+	if err != nil {
+		return fmt.Errorf("replacing file: %w", err)
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// syncDir syncs the directory to disk, so a file renamed into it survives a
+// crash. This is best effort, as not all platforms support syncing
+// directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	err = d.Sync()
+	if err != nil {
+		log.Debug("syncing directory", "dir", dir, "err", err)
+	}
+	_ = d.Close()
 }
 
 func (s *Store) Delete(key string) error {
@@ -178,7 +265,11 @@ func (s *Store) ListKeys(fromTo ...time.Time) ([]string, error) {
 	var keys []string
 
 	for _, entry := range entries {
-		name := strings.TrimSuffix(entry.Name(), ".yaml")
+		// skips temporary files left behind by an interrupted write
+		name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if !ok || entry.IsDir() {
+			continue
+		}
 		if from != "" && name < from {
 			continue
 		}
@@ -192,6 +283,41 @@ func (s *Store) ListKeys(fromTo ...time.Time) ([]string, error) {
 	return keys, nil
 }
 
+// Counts returns the number of records of each kind in the store, counting
+// the files on disk without reading them.
+func (s *Store) Counts() (Counts, error) {
+	var c Counts
+
+	keys, err := s.ListKeys()
+	if err != nil {
+		return c, fmt.Errorf("listing keys: %w", err)
+	}
+	c.Pomos = len(keys)
+
+	for _, n := range []struct {
+		key   string
+		count *int
+	}{
+		{currentPomo, &c.Snapshots},
+		{archiveKey, &c.Archived},
+		{planKey, &c.Plans},
+	} {
+		entries, err := os.ReadDir(filepath.Join(s.path, n.key))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return c, fmt.Errorf("reading %s directory: %w", n.key, err)
+		}
+		for _, entry := range entries {
+			if strings.HasSuffix(entry.Name(), ".yaml") && !entry.IsDir() {
+				*n.count++
+			}
+		}
+	}
+	return c, nil
+}
+
 func (s *Store) pomoFile(key string) string {
 	return filepath.Join(s.path, key+".yaml")
 }
@@ -1,11 +1,14 @@
 package store_test
 
 import (
+	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/clock"
 	"github.com/qualidafial/pomo/store"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,19 +18,46 @@ func TestStore(t *testing.T) {
 	now := time.Now().UTC().Truncate(time.Second)
 	p := pomo.Pomo{
 		Start: now,
-		End:   now.Add(25 * time.Minute),
+		End:   now.Add(30 * time.Minute),
+		Pauses: []pomo.Pause{
+			{
+				Start: now.Add(10 * time.Minute),
+				End:   now.Add(15 * time.Minute),
+			},
+		},
+		Interruptions: []pomo.Interruption{
+			{
+				Time: now.Add(5 * time.Minute),
+				Kind: pomo.Internal,
+				Note: "check email",
+			},
+			{
+				Time: now.Add(20 * time.Minute),
+				Kind: pomo.External,
+			},
+		},
 		Tasks: []pomo.Task{
 			{
+				ID:     "3f2a9c1e7b4d6a05",
 				Status: pomo.Todo,
 				Name:   "Paint the fence",
 				Notes:  "Up, down, up down",
 			},
 			{
-				Status: pomo.Doing,
-				Name:   "Wax the car",
-				Notes:  "Wax on, wax off",
+				ID:       "8c1d0e5f2a7b9346",
+				Status:   pomo.Doing,
+				Name:     "Wax the car",
+				Notes:    "Wax on, wax off",
+				Tags:     []string{"chores", "car"},
+				Estimate: 3,
+				Checklist: []pomo.ChecklistItem{
+					{Text: "Wash", Done: true},
+					{Text: "Wax on"},
+					{Text: "Wax off"},
+				},
 			},
 			{
+				ID:     "d4e6f8a0b2c41357",
 				Status: pomo.Done,
 				Name:   "Sand the floor",
 				Notes:  "Use little circles\nVery important",
@@ -40,7 +70,7 @@ func TestStore(t *testing.T) {
 
 	storePath := filepath.Join(tempDir, ".pomo")
 	t.Logf("store path: %s", storePath)
-	s, err := store.New(storePath)
+	s, err := store.New(storePath, clock.Real)
 	require.NoError(t, err)
 
 	err = s.Save("test", p)
@@ -50,3 +80,461 @@ func TestStore(t *testing.T) {
 	assert.Equal(t, p, loaded)
 	require.NoError(t, err)
 }
+
+// storages returns a constructor of an empty storage for each
+// implementation, by name.
+func storages() map[string]func(*testing.T, clock.Clock) store.Storage {
+	return map[string]func(*testing.T, clock.Clock) store.Storage{
+		"yaml": func(t *testing.T, c clock.Clock) store.Storage {
+			s, err := store.New(t.TempDir(), c)
+			require.NoError(t, err)
+			return s
+		},
+		"memory": func(t *testing.T, c clock.Clock) store.Storage {
+			return store.NewMemory(c)
+		},
+		"sqlite": func(t *testing.T, c clock.Clock) store.Storage {
+			return openSQLite(t, c)
+		},
+	}
+}
+
+func TestStorage_Current(t *testing.T) {
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	for name, newStorage := range storages() {
+		t.Run(name, func(t *testing.T) {
+			c := clock.NewFake(now)
+			s := newStorage(t, c)
+
+			current, err := s.GetCurrent()
+			require.NoError(t, err)
+			assert.Equal(t, pomo.Pomo{}, current)
+
+			older := pomo.Pomo{Tasks: []pomo.Task{{ID: "a", Status: pomo.Todo, Name: "Paint the fence"}}}
+			newer := pomo.Pomo{Tasks: []pomo.Task{{ID: "a", Status: pomo.Doing, Name: "Paint the fence"}}}
+			require.NoError(t, s.SaveCurrent(older))
+			c.Advance(time.Minute)
+			require.NoError(t, s.SaveCurrent(newer))
+
+			current, err = s.GetCurrent()
+			require.NoError(t, err)
+			assert.Equal(t, newer, current)
+
+			times, err := s.Snapshots()
+			require.NoError(t, err)
+			require.Equal(t, []time.Time{now.Add(time.Minute), now}, times)
+			snapshot, err := s.GetSnapshot(times[1])
+			require.NoError(t, err)
+			assert.Equal(t, older, snapshot)
+
+			require.NoError(t, s.ClearCurrent())
+			current, err = s.GetCurrent()
+			require.NoError(t, err)
+			assert.Equal(t, pomo.Pomo{}, current)
+		})
+	}
+}
+
+func TestStorage_List(t *testing.T) {
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	for name, newStorage := range storages() {
+		t.Run(name, func(t *testing.T) {
+			s := newStorage(t, clock.Real)
+
+			var pomos []pomo.Pomo
+			for i := range 3 {
+				start := now.Add(time.Duration(i) * time.Hour)
+				pomos = append(pomos, pomo.Pomo{
+					Start: start,
+					End:   start.Add(25 * time.Minute),
+					Tasks: []pomo.Task{{ID: "a", Status: pomo.Doing, Name: "Paint the fence"}},
+				})
+			}
+			// saved out of order
+			for _, i := range []int{2, 0, 1} {
+				require.NoError(t, s.SavePomo(pomos[i]))
+			}
+
+			all, err := s.List()
+			require.NoError(t, err)
+			assert.Equal(t, pomos, all)
+
+			from, err := s.List(pomos[1].End)
+			require.NoError(t, err)
+			assert.Equal(t, pomos[1:], from)
+
+			// the end of the range is exclusive
+			between, err := s.List(pomos[0].End, pomos[2].End)
+			require.NoError(t, err)
+			assert.Equal(t, pomos[:2], between)
+		})
+	}
+}
+
+func TestStorage_Archive(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	for name, newStorage := range storages() {
+		t.Run(name, func(t *testing.T) {
+			testArchive(t, newStorage(t, clock.Real), now)
+		})
+	}
+}
+
+func testArchive(t *testing.T, s store.Storage, now time.Time) {
+	archived, err := s.ListArchive()
+	require.NoError(t, err)
+	assert.Empty(t, archived)
+
+	fence := pomo.Task{
+		ID:     "3f2a9c1e7b4d6a05",
+		Status: pomo.Done,
+		Name:   "Paint the fence",
+		Tags:   []string{"chores"},
+	}
+	car := pomo.Task{
+		ID:     "8c1d0e5f2a7b9346",
+		Status: pomo.Done,
+		Name:   "Wax the car",
+		Notes:  "Wax on, wax off",
+	}
+	require.NoError(t, s.ArchiveTasks(now.Add(-time.Hour), []pomo.Task{fence}))
+	require.NoError(t, s.ArchiveTasks(now, []pomo.Task{car}))
+
+	archived, err = s.ListArchive()
+	require.NoError(t, err)
+	assert.Equal(t, []pomo.ArchivedTask{
+		{Task: car, ArchivedAt: now},
+		{Task: fence, ArchivedAt: now.Add(-time.Hour)},
+	}, archived)
+
+	require.NoError(t, s.Unarchive(car.ID))
+	archived, err = s.ListArchive()
+	require.NoError(t, err)
+	assert.Equal(t, []pomo.ArchivedTask{
+		{Task: fence, ArchivedAt: now.Add(-time.Hour)},
+	}, archived)
+
+	// a task without an ID could never be restored
+	err = s.ArchiveTasks(now, []pomo.Task{car, {Status: pomo.Done, Name: "Mow the lawn"}})
+	assert.ErrorContains(t, err, "no ID")
+	archived, err = s.ListArchive()
+	require.NoError(t, err)
+	assert.Len(t, archived, 1, "nothing is archived")
+}
+
+func TestStorage_Actuals(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	for name, newStorage := range storages() {
+		t.Run(name, func(t *testing.T) {
+			testActuals(t, newStorage(t, clock.Real), now)
+		})
+	}
+}
+
+func testActuals(t *testing.T, s store.Storage, now time.Time) {
+	fence := pomo.Task{ID: "3f2a9c1e7b4d6a05", Name: "Paint the fence"}
+	car := pomo.Task{ID: "8c1d0e5f2a7b9346", Name: "Wax the car"}
+	pomos := []pomo.Pomo{
+		{
+			Start: now.Add(-2 * time.Hour),
+			End:   now.Add(-95 * time.Minute),
+			Tasks: []pomo.Task{fence, car},
+		},
+		{
+			Start: now.Add(-time.Hour),
+			End:   now.Add(-35 * time.Minute),
+			Tasks: []pomo.Task{fence},
+		},
+		{
+			Start:   now.Add(-30 * time.Minute),
+			End:     now.Add(-20 * time.Minute),
+			Outcome: pomo.Cancelled,
+			Tasks:   []pomo.Task{car},
+		},
+	}
+	for _, p := range pomos {
+		require.NoError(t, s.SavePomo(p))
+	}
+
+	actuals, err := s.Actuals()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		fence.ID: 2,
+		car.ID:   1,
+	}, actuals)
+}
+
+func TestStorage_Plan(t *testing.T) {
+	for name, newStorage := range storages() {
+		t.Run(name, func(t *testing.T) {
+			testPlan(t, newStorage(t, clock.Real))
+		})
+	}
+}
+
+func testPlan(t *testing.T, s store.Storage) {
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.Local)
+
+	_, ok, err := s.GetPlan(day)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	plan := pomo.Plan{
+		Day: day,
+		Tasks: []pomo.Task{
+			{ID: "3f2a9c1e7b4d6a05", Status: pomo.Todo, Name: "Paint the fence", Estimate: 2},
+			{ID: "8c1d0e5f2a7b9346", Status: pomo.Doing, Name: "Wax the car", Estimate: 3},
+		},
+	}
+	require.NoError(t, s.SavePlan(plan))
+
+	got, ok, err := s.GetPlan(day)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, plan, got)
+	assert.Equal(t, 5, got.Estimate())
+
+	_, ok, err = s.GetPlan(day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestStore_GetCurrentRecovers(t *testing.T) {
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	c := clock.NewFake(now)
+	dir := t.TempDir()
+
+	s, err := store.New(dir, c)
+	require.NoError(t, err)
+
+	older := pomo.Pomo{Tasks: []pomo.Task{{ID: "a", Name: "Paint the fence"}}}
+	newer := pomo.Pomo{Tasks: []pomo.Task{{ID: "a", Name: "Paint the fence"}, {ID: "b", Name: "Wax the car"}}}
+	require.NoError(t, s.SaveCurrent(older))
+	c.Advance(time.Minute)
+	require.NoError(t, s.SaveCurrent(newer))
+
+	// no temporary files are left behind
+	temps, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, temps)
+
+	// a truncated file falls back to the newest snapshot
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "current.yaml"), nil, 0o600))
+	got, err := s.GetCurrent()
+	require.NoError(t, err)
+	assert.Equal(t, newer, got)
+
+	// an unreadable snapshot falls back to the one before it
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "current", "2024-03-05_100100.yaml"), []byte("tasks: [\n"), 0o600))
+	got, err = s.GetCurrent()
+	require.NoError(t, err)
+	assert.Equal(t, older, got)
+
+	// a temporary file left by an interrupted write is not history
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "history", ".2024-03-05_100000.yaml.123.tmp"), []byte("start: ["), 0o600))
+	pomos, err := s.List()
+	require.NoError(t, err)
+	assert.Empty(t, pomos)
+}
+
+func TestStorage_PruneSnapshots(t *testing.T) {
+	pruners := map[string]func(*testing.T, clock.Clock) store.Pruner{
+		"yaml": func(t *testing.T, c clock.Clock) store.Pruner {
+			s, err := store.New(t.TempDir(), c)
+			require.NoError(t, err)
+			return s
+		},
+		"sqlite": func(t *testing.T, c clock.Clock) store.Pruner {
+			return openSQLite(t, c)
+		},
+	}
+	for name, newPruner := range pruners {
+		t.Run(name, func(t *testing.T) {
+			testPruneSnapshots(t, newPruner)
+		})
+	}
+}
+
+func testPruneSnapshots(t *testing.T, newPruner func(*testing.T, clock.Clock) store.Pruner) {
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.Local)
+	c := clock.NewFake(day)
+	p := newPruner(t, c)
+	s := p.(store.Storage)
+
+	times := []time.Time{
+		day.Add(8 * time.Hour),
+		day.AddDate(0, 0, 1).Add(10 * time.Hour),
+		day.AddDate(0, 0, 1).Add(10*time.Hour + 30*time.Minute),
+		day.AddDate(0, 0, 1).Add(11 * time.Hour),
+		day.AddDate(0, 0, 2).Add(9 * time.Hour),
+		day.AddDate(0, 0, 2).Add(9*time.Hour + 10*time.Minute),
+		day.AddDate(0, 0, 2).Add(9*time.Hour + 20*time.Minute),
+		day.AddDate(0, 0, 2).Add(9*time.Hour + 30*time.Minute),
+	}
+	for _, tm := range times {
+		c.Set(tm)
+		require.NoError(t, s.SaveCurrent(pomo.Pomo{Start: tm}))
+	}
+
+	// snapshots returns the indexes in times of the snapshots left
+	snapshots := func() []int {
+		left, err := s.Snapshots()
+		require.NoError(t, err)
+		var indexes []int
+		for i := len(times) - 1; i >= 0; i-- {
+			if slices.ContainsFunc(left, times[i].Equal) {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+	require.Len(t, snapshots(), len(times))
+
+	p.SetRetention(store.Retention{Recent: 2, Hourly: 2, Daily: 2})
+
+	result, err := p.PruneSnapshots(true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Kept)
+	assert.Equal(t, 5, result.Pruned)
+	assert.Positive(t, result.Freed)
+	assert.Len(t, snapshots(), len(times), "dry run deletes nothing")
+
+	result, err = p.PruneSnapshots(false)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Pruned)
+
+	// the two most recent, and the newest of the second day and of its last
+	// hour
+	assert.Equal(t, []int{7, 6, 3}, snapshots())
+
+	// the newest snapshot is always kept
+	p.SetRetention(store.Retention{})
+	result, err = p.PruneSnapshots(false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Kept)
+	assert.Equal(t, []int{7}, snapshots())
+}
+
+func TestAutoPrune(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC))
+	s, err := store.New(t.TempDir(), c)
+	require.NoError(t, err)
+	s.SetRetention(store.Retention{Recent: 1})
+
+	for range 3 {
+		require.NoError(t, s.SaveCurrent(pomo.Pomo{}))
+		c.Advance(time.Minute)
+	}
+	snapshots, err := s.Snapshots()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3, "saving doesn't prune")
+
+	// prunes once before checking whether to stop
+	stop := make(chan struct{})
+	close(stop)
+	store.AutoPrune(s, c, stop)
+
+	snapshots, err = s.Snapshots()
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+}
+
+func TestSQLite_Import(t *testing.T) {
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	c := clock.NewFake(now)
+	dir := t.TempDir()
+
+	src, err := store.New(dir, c)
+	require.NoError(t, err)
+
+	fence := pomo.Task{ID: "3f2a9c1e7b4d6a05", Status: pomo.Doing, Name: "Paint the fence"}
+	car := pomo.Task{ID: "8c1d0e5f2a7b9346", Status: pomo.Done, Name: "Wax the car"}
+	for i := range 3 {
+		start := now.Add(time.Duration(i-3) * time.Hour)
+		require.NoError(t, src.SavePomo(pomo.Pomo{
+			Start: start,
+			End:   start.Add(25 * time.Minute),
+			Tasks: []pomo.Task{fence},
+		}))
+	}
+	require.NoError(t, src.ArchiveTasks(now, []pomo.Task{car}))
+	require.NoError(t, src.SavePlan(pomo.Plan{Day: time.Date(2024, 3, 5, 0, 0, 0, 0, time.Local), Tasks: []pomo.Task{fence}}))
+	require.NoError(t, src.SaveCurrent(pomo.Pomo{Tasks: []pomo.Task{fence}}))
+	c.Advance(time.Minute)
+	require.NoError(t, src.SaveCurrent(pomo.Pomo{Start: now, Tasks: []pomo.Task{fence}}))
+
+	dst := openSQLite(t, c)
+	// stale records are replaced
+	require.NoError(t, dst.ArchiveTasks(now, []pomo.Task{fence}))
+
+	counts, err := dst.Import(src)
+	require.NoError(t, err)
+	assert.Equal(t, store.Counts{Pomos: 3, Snapshots: 2, Archived: 1, Plans: 1}, counts)
+
+	for _, get := range []func(store.Storage) (any, error){
+		func(s store.Storage) (any, error) { return s.GetCurrent() },
+		func(s store.Storage) (any, error) { return s.List() },
+		func(s store.Storage) (any, error) { return s.Actuals() },
+		func(s store.Storage) (any, error) { return s.ListArchive() },
+		func(s store.Storage) (any, error) { return s.Snapshots() },
+	} {
+		want, err := get(src)
+		require.NoError(t, err)
+		got, err := get(dst)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	// a file that isn't a snapshot written by the store is not imported, and
+	// nothing else is either
+	require.NoError(t, src.SavePomo(pomo.Pomo{Start: now, End: now.Add(25 * time.Minute)}))
+	stray := filepath.Join(dir, "current", "backup.yaml")
+	require.NoError(t, os.WriteFile(stray, []byte("tasks: []\n"), 0o600))
+	_, err = dst.Import(src)
+	assert.ErrorContains(t, err, "verifying import")
+	require.NoError(t, os.Remove(stray))
+
+	// a failed import leaves the database as it was
+	existing, err := dst.Counts()
+	require.NoError(t, err)
+	assert.Equal(t, counts, existing)
+
+	// an unreadable snapshot fails the import instead of being dropped
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "current", "2024-03-05_100000.yaml"), []byte("tasks: [\n"), 0o600))
+	_, err = dst.Import(src)
+	assert.ErrorContains(t, err, "reading snapshot")
+}
+
+func TestSQLite_ListByTask(t *testing.T) {
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	s := openSQLite(t, clock.NewFake(now))
+
+	fence := pomo.Task{ID: "3f2a9c1e7b4d6a05", Name: "Paint the fence"}
+	car := pomo.Task{ID: "8c1d0e5f2a7b9346", Name: "Wax the car"}
+	var pomos []pomo.Pomo
+	for i, tasks := range [][]pomo.Task{{fence}, {car}, {fence, car}} {
+		start := now.Add(time.Duration(i-3) * time.Hour)
+		p := pomo.Pomo{Start: start, End: start.Add(25 * time.Minute), Tasks: tasks}
+		require.NoError(t, s.SavePomo(p))
+		pomos = append(pomos, p)
+	}
+
+	got, err := s.ListByTask(fence.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []pomo.Pomo{pomos[0], pomos[2]}, got)
+
+	got, err = s.ListByTask("0000000000000000")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func openSQLite(t *testing.T, c clock.Clock) *store.SQLite {
+	t.Helper()
+
+	s, err := store.OpenSQLite(filepath.Join(t.TempDir(), "pomo.db"), c)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, s.Close())
+	})
+	return s
+}
@@ -1,92 +1,202 @@
 package pomo
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 )
 
-type Status int
+// Status is the status of the workflow column a task is in.
+type Status string
 
+// Statuses of the default workflow.
 const (
-	Todo Status = iota
-	Doing
-	Done
+	Todo  Status = "todo"
+	Doing Status = "doing"
+	Done  Status = "done"
 )
 
 func (s Status) String() string {
-	switch s {
-	case Todo:
-		return "todo"
-	case Doing:
-		return "doing"
-	case Done:
-		return "done"
-	default:
-		return "unknown"
-	}
+	return string(s)
 }
 
-func ParseStatus(s string) (Status, error) {
-	switch s {
-	case "todo":
-		return Todo, nil
-	case "doing":
-		return Doing, nil
-	case "done":
-		return Done, nil
-	default:
-		return 0, fmt.Errorf("unknown status: %s", s)
+// NewTaskID returns a new random identifier for a task. Task IDs are assigned
+// once when a task is created and never change, so the same task can be
+// matched across the current board and pomodoro history even if renamed.
+func NewTaskID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Errorf("generating task id: %w", err))
 	}
+	return hex.EncodeToString(b[:])
 }
 
 type Task struct {
+	ID        string
 	Status    Status
 	UpdatedAt time.Time
 	Name      string
 	Notes     string
+	Tags      []string
+	// Estimate is the number of pomodoros the task is expected to take, or
+	// zero if not estimated.
+	Estimate  int
+	Checklist []ChecklistItem
+}
+
+// ChecklistItem is a step of a task that can be checked off.
+type ChecklistItem struct {
+	Text string
+	Done bool
+}
+
+// Progress returns the number of checklist items that are done, and the total
+// number of checklist items.
+func (t Task) Progress() (done, total int) {
+	for _, item := range t.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(t.Checklist)
 }
 
 func (t Task) MarshalYAML() (any, error) {
+	return t.yaml(), nil
+}
+
+func (t *Task) UnmarshalYAML(unmarshal func(any) error) error {
+	var data task
+	if err := unmarshal(&data); err != nil {
+		return err
+	}
+
+	task, err := data.parse()
+	if err != nil {
+		return err
+	}
+	*t = task
+	return nil
+}
+
+func (t Task) yaml() task {
 	var updatedAt string
 	if !t.UpdatedAt.IsZero() {
 		updatedAt = t.UpdatedAt.Format(time.RFC3339Nano)
 	}
 	return task{
+		ID:        t.ID,
 		Status:    t.Status.String(),
 		Name:      t.Name,
 		Notes:     t.Notes,
+		Tags:      t.Tags,
+		Estimate:  t.Estimate,
+		Checklist: checklistYAML(t.Checklist),
+		UpdatedAt: updatedAt,
+	}
+}
+
+func checklistYAML(items []ChecklistItem) []checklistItem {
+	if len(items) == 0 {
+		return nil
+	}
+	data := make([]checklistItem, len(items))
+	for i, item := range items {
+		data[i] = checklistItem{
+			Text: item.Text,
+			Done: item.Done,
+		}
+	}
+	return data
+}
+
+func (data task) parse() (Task, error) {
+	updatedAt, err := parseTime(data.UpdatedAt)
+	if err != nil {
+		return Task{}, err
+	}
+
+	return Task{
+		ID:        data.ID,
+		Status:    Status(data.Status),
+		Name:      data.Name,
+		Notes:     data.Notes,
+		Tags:      data.Tags,
+		Estimate:  data.Estimate,
+		Checklist: data.parseChecklist(),
 		UpdatedAt: updatedAt,
 	}, nil
 }
 
-func (t *Task) UnmarshalYAML(unmarshal func(any) error) error {
-	var data task
+func (data task) parseChecklist() []ChecklistItem {
+	if len(data.Checklist) == 0 {
+		return nil
+	}
+	items := make([]ChecklistItem, len(data.Checklist))
+	for i, item := range data.Checklist {
+		items[i] = ChecklistItem{
+			Text: item.Text,
+			Done: item.Done,
+		}
+	}
+	return items
+}
+
+type task struct {
+	ID        string          `yaml:"id,omitempty"`
+	Status    string          `yaml:"status"`
+	Name      string          `yaml:"name"`
+	Notes     string          `yaml:"notes,omitempty"`
+	Tags      []string        `yaml:"tags,omitempty"`
+	Estimate  int             `yaml:"estimate,omitempty"`
+	Checklist []checklistItem `yaml:"checklist,omitempty"`
+	UpdatedAt string          `yaml:"updatedAt,omitempty"`
+}
+
+type checklistItem struct {
+	Text string `yaml:"text"`
+	Done bool   `yaml:"done,omitempty"`
+}
+
+// ArchivedTask is a done task that was removed from the board when a pomodoro
+// was completed.
+type ArchivedTask struct {
+	Task
+	ArchivedAt time.Time
+}
+
+func (t ArchivedTask) MarshalYAML() (any, error) {
+	return archivedTask{
+		task:       t.Task.yaml(),
+		ArchivedAt: t.ArchivedAt.Format(time.RFC3339Nano),
+	}, nil
+}
+
+func (t *ArchivedTask) UnmarshalYAML(unmarshal func(any) error) error {
+	var data archivedTask
 	if err := unmarshal(&data); err != nil {
 		return err
 	}
 
-	status, err := ParseStatus(data.Status)
+	task, err := data.task.parse()
 	if err != nil {
 		return err
 	}
 
-	updatedAt, err := parseTime(data.UpdatedAt)
+	archivedAt, err := parseTime(data.ArchivedAt)
 	if err != nil {
 		return err
 	}
 
-	*t = Task{
-		Status:    status,
-		Name:      data.Name,
-		Notes:     data.Notes,
-		UpdatedAt: updatedAt,
+	*t = ArchivedTask{
+		Task:       task,
+		ArchivedAt: archivedAt,
 	}
 	return nil
 }
 
-type task struct {
-	Status    string `yaml:"status"`
-	Name      string `yaml:"name"`
-	Notes     string `yaml:"notes,omitempty"`
-	UpdatedAt string `yaml:"updatedAt,omitempty"`
+type archivedTask struct {
+	task       `yaml:",inline"`
+	ArchivedAt string `yaml:"archivedAt"`
 }
@@ -11,6 +11,15 @@ type KeyMap struct {
 	Save   key.Binding
 	Enter  key.Binding
 	Cancel key.Binding
+
+	// checklist keys, enabled while the checklist is focused
+	PrevItem     key.Binding
+	NextItem     key.Binding
+	AddItem      key.Binding
+	ToggleItem   key.Binding
+	MoveItemUp   key.Binding
+	MoveItemDown key.Binding
+	RemoveItem   key.Binding
 }
 
 func DefaultKeyMap() KeyMap {
@@ -36,6 +45,35 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "cancel"),
 		),
+
+		PrevItem: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "previous item"),
+		),
+		NextItem: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "next item"),
+		),
+		AddItem: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "add item"),
+		),
+		ToggleItem: key.NewBinding(
+			key.WithKeys(" ", "x"),
+			key.WithHelp("space", "check/uncheck"),
+		),
+		MoveItemUp: key.NewBinding(
+			key.WithKeys("shift+up", "K"),
+			key.WithHelp("shift+↑", "move item up"),
+		),
+		MoveItemDown: key.NewBinding(
+			key.WithKeys("shift+down", "J"),
+			key.WithHelp("shift+↓", "move item down"),
+		),
+		RemoveItem: key.NewBinding(
+			key.WithKeys("delete", "backspace", "-"),
+			key.WithHelp("del", "remove item"),
+		),
 	}
 }
 
@@ -49,6 +87,17 @@ func (m KeyMap) FullHelp() [][]key.Binding {
 			m.NextField,
 			m.PrevField,
 		},
+		{
+			m.PrevItem,
+			m.NextItem,
+			m.AddItem,
+			m.ToggleItem,
+		},
+		{
+			m.MoveItemUp,
+			m.MoveItemDown,
+			m.RemoveItem,
+		},
 	}
 }
 
@@ -58,5 +107,8 @@ func (m KeyMap) ShortHelp() []key.Binding {
 		m.Cancel,
 		m.NextField,
 		m.PrevField,
+		m.AddItem,
+		m.ToggleItem,
+		m.RemoveItem,
 	}
 }
@@ -7,6 +7,10 @@ import (
 
 type Styles struct {
 	Frame lipgloss.Style
+
+	Item         lipgloss.Style
+	DoneItem     lipgloss.Style
+	SelectedItem lipgloss.Style
 }
 
 func DefaultStyles() Styles {
@@ -15,5 +19,16 @@ func DefaultStyles() Styles {
 			Padding(0, 1).
 			Border(lipgloss.NormalBorder()).
 			BorderForeground(color.Cyan),
+
+		Item: lipgloss.NewStyle().
+			Padding(0, 0, 0, 2),
+		DoneItem: lipgloss.NewStyle().
+			Padding(0, 0, 0, 2).
+			Foreground(color.Gray).
+			Strikethrough(true),
+		SelectedItem: lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(color.Cyan).
+			Padding(0, 0, 0, 1),
 	}
 }
@@ -1,12 +1,20 @@
 package taskedit
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
 	"github.com/qualidafial/pomo"
 	"github.com/qualidafial/pomo/message"
 )
@@ -15,6 +23,9 @@ type field int
 
 const (
 	summary field = iota
+	tags
+	estimate
+	checklist
 	notes
 )
 
@@ -25,11 +36,20 @@ type Model struct {
 	maxWidth  int
 	maxHeight int
 
-	status pomo.Status
+	// task being edited, overlaid with the field values in Task()
+	task pomo.Task
+
+	focused  field
+	name     textinput.Model
+	tags     textinput.Model
+	estimate textinput.Model
+	notes    textarea.Model
 
-	focused field
-	name    textinput.Model
-	notes   textarea.Model
+	// items is the checklist, and item the index of the selected item, or
+	// len(items) when the input for a new item is selected.
+	items   []pomo.ChecklistItem
+	item    int
+	newItem textinput.Model
 
 	help help.Model
 }
@@ -40,16 +60,31 @@ func New() Model {
 	title := textinput.New()
 	title.Placeholder = "name here"
 
+	tags := textinput.New()
+	tags.Placeholder = "tags here, separated by spaces or commas"
+
+	estimate := textinput.New()
+	estimate.Placeholder = "pomodoros"
+	estimate.CharLimit = 2
+	estimate.Validate = validateEstimate
+
 	notes := textarea.New()
 	notes.ShowLineNumbers = false
 	notes.Placeholder = "notes here"
 
+	newItem := textinput.New()
+	newItem.Prompt = "+ "
+	newItem.Placeholder = "new checklist item"
+
 	return Model{
 		Styles: styles,
 		KeyMap: DefaultKeyMap(),
 
-		name:  title,
-		notes: notes,
+		name:     title,
+		tags:     tags,
+		estimate: estimate,
+		notes:    notes,
+		newItem:  newItem,
 
 		help: help.New(),
 	}
@@ -68,30 +103,89 @@ func (m *Model) focusField(f field) tea.Cmd {
 	}
 	m.focused = f
 
+	m.name.Blur()
+	m.tags.Blur()
+	m.estimate.Blur()
+	m.newItem.Blur()
+	m.notes.Blur()
+
 	switch f {
 	case summary:
-		m.notes.Blur()
 		return m.name.Focus()
+	case tags:
+		return m.tags.Focus()
+	case estimate:
+		return m.estimate.Focus()
+	case checklist:
+		return m.selectItem(len(m.items))
 	case notes:
-		m.name.Blur()
 		return m.notes.Focus()
 	}
 	return nil
 }
 
+// selectItem selects the checklist item at the given index, or the input for
+// a new item past the last item.
+func (m *Model) selectItem(index int) tea.Cmd {
+	m.item = max(0, min(index, len(m.items)))
+	if m.item == len(m.items) {
+		return m.newItem.Focus()
+	}
+	m.newItem.Blur()
+	return nil
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
+		case key.Matches(msg, m.KeyMap.AddItem):
+			m.items = append(m.items, pomo.ChecklistItem{
+				Text: strings.TrimSpace(m.newItem.Value()),
+			})
+			m.newItem.Reset()
+			cmd := m.selectItem(len(m.items))
+			m.enableKeys()
+			m.layout()
+			return m, cmd
+		case key.Matches(msg, m.KeyMap.ToggleItem):
+			m.items[m.item].Done = !m.items[m.item].Done
+			return m, nil
+		case key.Matches(msg, m.KeyMap.MoveItemUp):
+			m.items[m.item], m.items[m.item-1] = m.items[m.item-1], m.items[m.item]
+			m.item--
+			m.enableKeys()
+			return m, nil
+		case key.Matches(msg, m.KeyMap.MoveItemDown):
+			m.items[m.item], m.items[m.item+1] = m.items[m.item+1], m.items[m.item]
+			m.item++
+			m.enableKeys()
+			return m, nil
+		case key.Matches(msg, m.KeyMap.RemoveItem):
+			m.items = slices.Delete(m.items, m.item, m.item+1)
+			cmd := m.selectItem(m.item)
+			m.enableKeys()
+			m.layout()
+			return m, cmd
+		case key.Matches(msg, m.KeyMap.PrevItem):
+			cmd := m.selectItem(m.item - 1)
+			m.enableKeys()
+			return m, cmd
+		case key.Matches(msg, m.KeyMap.NextItem):
+			cmd := m.selectItem(m.item + 1)
+			m.enableKeys()
+			return m, cmd
 		case key.Matches(msg, m.KeyMap.Save) || key.Matches(msg, m.KeyMap.Enter):
 			return m, message.SaveTask(m.Task())
 		case key.Matches(msg, m.KeyMap.Cancel):
 			return m, message.CancelEdit
 		case key.Matches(msg, m.KeyMap.NextField):
 			cmd := m.focusField(m.focused + 1)
+			m.enableKeys()
 			return m, cmd
 		case key.Matches(msg, m.KeyMap.PrevField):
 			cmd := m.focusField(m.focused - 1)
+			m.enableKeys()
 			return m, cmd
 		}
 	}
@@ -101,6 +195,12 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch m.focused {
 	case summary:
 		m.name, cmd = m.name.Update(msg)
+	case tags:
+		m.tags, cmd = m.tags.Update(msg)
+	case estimate:
+		m.estimate, cmd = m.estimate.Update(msg)
+	case checklist:
+		m.newItem, cmd = m.newItem.Update(msg)
 	case notes:
 		m.notes, cmd = m.notes.Update(msg)
 	}
@@ -111,26 +211,54 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 }
 
 func (m *Model) enableKeys() {
+	onChecklist := m.focused == checklist
+	onItem := onChecklist && m.item < len(m.items)
+	onNewItem := onChecklist && m.item == len(m.items)
+
+	m.KeyMap.PrevItem.SetEnabled(onChecklist && m.item > 0)
+	m.KeyMap.NextItem.SetEnabled(onItem)
+	m.KeyMap.AddItem.SetEnabled(onNewItem && strings.TrimSpace(m.newItem.Value()) != "")
+	m.KeyMap.ToggleItem.SetEnabled(onItem)
+	m.KeyMap.MoveItemUp.SetEnabled(onItem && m.item > 0)
+	m.KeyMap.MoveItemDown.SetEnabled(onItem && m.item+1 < len(m.items))
+	m.KeyMap.RemoveItem.SetEnabled(onItem)
+
 	m.KeyMap.Save.SetEnabled(m.name.Value() != "")
-	m.KeyMap.Enter.SetEnabled(m.KeyMap.Save.Enabled() && m.focused == summary)
+	m.KeyMap.Enter.SetEnabled(m.KeyMap.Save.Enabled() && m.focused != notes && !onItem)
 }
 
 func (m Model) Task() pomo.Task {
-	return pomo.Task{
-		Status: m.status,
-		Name:   m.name.Value(),
-		Notes:  m.notes.Value(),
-	}
+	task := m.task
+	task.Name = m.name.Value()
+	task.Notes = m.notes.Value()
+	task.Tags = parseTags(m.tags.Value())
+	task.Estimate, _ = strconv.Atoi(m.estimate.Value())
+	task.Checklist = slices.Clone(m.items)
+	return task
 }
 
 func (m *Model) SetTask(task pomo.Task) {
-	m.status = task.Status
+	m.task = task
 	m.name.Reset()
 	m.name.SetValue(task.Name)
 
+	m.tags.Reset()
+	m.tags.SetValue(strings.Join(task.Tags, " "))
+
+	m.estimate.Reset()
+	if task.Estimate > 0 {
+		m.estimate.SetValue(strconv.Itoa(task.Estimate))
+	}
+
+	m.items = slices.Clone(task.Checklist)
+	m.item = len(m.items)
+	m.newItem.Reset()
+
 	m.notes.Reset()
 	m.notes.SetValue(task.Notes)
 
+	m.layout()
+
 	m.enableKeys()
 }
 
@@ -139,6 +267,12 @@ func (m Model) View() string {
 		lipgloss.JoinVertical(lipgloss.Left,
 			m.viewName(),
 			"",
+			m.viewTags(),
+			"",
+			m.viewEstimate(),
+			"",
+			m.viewChecklist(),
+			"",
 			m.viewNotes(),
 			"",
 			m.viewHelp(),
@@ -154,6 +288,46 @@ func (m Model) viewName() string {
 	)
 }
 
+func (m Model) viewTags() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"Tags:",
+		m.tags.View(),
+	)
+}
+
+func (m Model) viewEstimate() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"Estimate:",
+		m.estimate.View(),
+	)
+}
+
+func (m Model) viewChecklist() string {
+	title := "Checklist:"
+	if done, total := m.Task().Progress(); total > 0 {
+		title = fmt.Sprintf("Checklist (%d/%d):", done, total)
+	}
+
+	lines := []string{title}
+	for i, item := range m.items {
+		check := "[ ] "
+		style := m.Styles.Item
+		if item.Done {
+			check = "[x] "
+			style = m.Styles.DoneItem
+		}
+		if m.focused == checklist && i == m.item {
+			style = m.Styles.SelectedItem.Copy().Inherit(style)
+		}
+		text := truncate.StringWithTail(item.Text, uint(max(0, m.newItem.Width)), "…")
+		lines = append(lines, style.Render(check+text))
+	}
+	lines = append(lines, m.newItem.View())
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) viewNotes() string {
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -180,8 +354,11 @@ func (m *Model) layout() {
 	h := m.maxHeight - m.Styles.Frame.GetVerticalFrameSize()
 
 	m.name.Width = w - 3
+	m.tags.Width = w - 3
+	m.estimate.Width = w - 3
+	m.newItem.Width = w - 5
 
-	notesHeight := h - 4
+	notesHeight := h - 13 - len(m.items)
 	if notesHeight > 4 {
 		notesHeight = 4
 	}
@@ -190,3 +367,29 @@ func (m *Model) layout() {
 
 	m.help.Width = w
 }
+
+// validateEstimate accepts an empty estimate or a number of pomodoros.
+func validateEstimate(s string) error {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return errors.New("estimate must be a number of pomodoros")
+		}
+	}
+	return nil
+}
+
+// parseTags splits the given string into tags separated by whitespace or
+// commas, dropping empty and duplicate tags.
+func parseTags(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	var tags []string
+	for _, tag := range fields {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,62 @@
+package taskedit_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/taskedit"
+	"github.com/stretchr/testify/assert"
+)
+
+func keys(s string) []tea.KeyMsg {
+	var msgs []tea.KeyMsg
+	for _, r := range s {
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return msgs
+}
+
+func TestChecklist(t *testing.T) {
+	m := taskedit.New()
+	m.SetMaxSize(80, 40)
+	m.SetTask(pomo.Task{
+		ID:   "a",
+		Name: "Wax the car",
+		Checklist: []pomo.ChecklistItem{
+			{Text: "Wash"},
+		},
+	})
+	m.Focus()
+
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	up := tea.KeyMsg{Type: tea.KeyUp}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	shiftUp := tea.KeyMsg{Type: tea.KeyShiftUp}
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	// name, tags, estimate, then the checklist
+	var msgs []tea.KeyMsg
+	msgs = append(msgs, tab, tab, tab)
+	msgs = append(msgs, keys("Wax on")...)
+	msgs = append(msgs, enter)
+	msgs = append(msgs, keys("Wax off")...)
+	msgs = append(msgs, enter)
+	// move "Wax off" to the top, and check off "Wash"
+	msgs = append(msgs, up, shiftUp, shiftUp, down, space)
+	for _, msg := range msgs {
+		m, _ = m.Update(msg)
+	}
+
+	task := m.Task()
+	assert.Equal(t, []pomo.ChecklistItem{
+		{Text: "Wax off"},
+		{Text: "Wash", Done: true},
+		{Text: "Wax on"},
+	}, task.Checklist)
+	done, total := task.Progress()
+	assert.Equal(t, 1, done)
+	assert.Equal(t, 3, total)
+	assert.Contains(t, m.View(), "Checklist (1/3):")
+}
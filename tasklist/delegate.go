@@ -0,0 +1,168 @@
+package tasklist
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/qualidafial/pomo/color"
+)
+
+const ellipsis = "…"
+
+// delegate renders task items like list.DefaultDelegate, with a badge of
+// pomodoros worked against the estimate, the status of a task that doesn't
+// belong in the list and the task tags rendered as colored chips at the end of
+// the title line, and the runes of the task name matching the filter
+// highlighted.
+type delegate struct {
+	list.DefaultDelegate
+
+	badge         lipgloss.Style
+	overEstimated lipgloss.Style
+	misplaced     lipgloss.Style
+}
+
+func newDelegate(focused bool) delegate {
+	d := list.NewDefaultDelegate()
+	if !focused {
+		d.Styles.SelectedTitle = d.Styles.NormalTitle
+		d.Styles.SelectedDesc = d.Styles.NormalDesc
+	}
+	return delegate{
+		DefaultDelegate: d,
+
+		badge: lipgloss.NewStyle().
+			Foreground(color.Gray),
+		overEstimated: lipgloss.NewStyle().
+			Foreground(color.BrightRed),
+		misplaced: lipgloss.NewStyle().
+			Foreground(color.BrightRed),
+	}
+}
+
+func (d delegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(Item)
+	if !ok {
+		d.DefaultDelegate.Render(w, m, index, listItem)
+		return
+	}
+	if m.Width() <= 0 {
+		return
+	}
+
+	s := &d.Styles
+	titleStyle, descStyle := s.NormalTitle, s.NormalDesc
+	if index == m.Index() {
+		titleStyle, descStyle = s.SelectedTitle, s.SelectedDesc
+	}
+
+	textWidth := m.Width() - s.NormalTitle.GetHorizontalPadding()
+	status := d.viewStatus(i)
+	badge := d.viewBadge(i)
+	tags := viewTags(i.Tags, textWidth/2-lipgloss.Width(status)-lipgloss.Width(badge))
+	nameWidth := textWidth
+	for _, suffix := range []string{status, badge, tags} {
+		if suffix != "" {
+			nameWidth = max(0, nameWidth-lipgloss.Width(suffix)-1)
+		}
+	}
+
+	title := truncate.StringWithTail(i.Name, uint(nameWidth), ellipsis)
+	if len(i.matches) > 0 {
+		unmatched := titleStyle.Inline(true)
+		matched := unmatched.Copy().Inherit(s.FilterMatch)
+		title = lipgloss.StyleRunes(title, i.matches, matched, unmatched)
+	}
+	title = titleStyle.Render(title)
+	for _, suffix := range []string{status, badge, tags} {
+		if suffix != "" {
+			title += " " + suffix
+		}
+	}
+
+	if !d.ShowDescription {
+		_, _ = io.WriteString(w, title)
+		return
+	}
+
+	var lines []string
+	for n, line := range strings.Split(i.Description(), "\n") {
+		if n >= d.Height()-1 {
+			break
+		}
+		lines = append(lines, truncate.StringWithTail(line, uint(textWidth), ellipsis))
+	}
+	desc := descStyle.Render(strings.Join(lines, "\n"))
+
+	_, _ = io.WriteString(w, title+"\n"+desc)
+}
+
+// viewStatus renders the status of a task that doesn't belong in the list,
+// e.g. "? review" for a task whose column was removed from the workflow.
+// Returns an empty string if the task belongs in the list.
+func (d delegate) viewStatus(i Item) string {
+	if !i.misplaced {
+		return ""
+	}
+	return d.misplaced.Render("? " + string(i.Status))
+}
+
+// viewBadge renders the pomodoros the task was worked on against its
+// estimate, e.g. "2/3 🍅", in a warning color once the estimate is exceeded.
+// Returns an empty string if the task has neither.
+func (d delegate) viewBadge(i Item) string {
+	switch {
+	case i.Estimate > 0 && i.actual > i.Estimate:
+		return d.overEstimated.Render(fmt.Sprintf("%d/%d 🍅", i.actual, i.Estimate))
+	case i.Estimate > 0:
+		return d.badge.Render(fmt.Sprintf("%d/%d 🍅", i.actual, i.Estimate))
+	case i.actual > 0:
+		return d.badge.Render(fmt.Sprintf("%d 🍅", i.actual))
+	default:
+		return ""
+	}
+}
+
+// viewTags renders as many tags as chips as will fit within the given width.
+func viewTags(tags []string, width int) string {
+	var chips []string
+	remaining := width
+	for _, tag := range tags {
+		chip := TagStyle(tag).Render(tag)
+		w := lipgloss.Width(chip)
+		if len(chips) > 0 {
+			w++
+		}
+		if w > remaining {
+			break
+		}
+		remaining -= w
+		chips = append(chips, chip)
+	}
+	return strings.Join(chips, " ")
+}
+
+// TagStyle returns the chip style for the given tag. Each tag is assigned a
+// background color derived from its name, so a tag has the same color
+// everywhere it appears.
+func TagStyle(tag string) lipgloss.Style {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(tag))
+	sum := h.Sum32()
+
+	// pick a mid-intensity color from the 6x6x6 color cube, avoiding colors
+	// too dark or too light for the foreground text to be readable.
+	red := float64(1+sum%4) / 5
+	green := float64(1+(sum/4)%4) / 5
+	blue := float64(1+(sum/16)%4) / 5
+
+	return lipgloss.NewStyle().
+		Padding(0, 1).
+		Foreground(color.Black).
+		Background(color.ANSI256ColorCube(red, green, blue))
+}
@@ -1,6 +1,11 @@
 package tasklist
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -10,11 +15,20 @@ import (
 
 type Item struct {
 	pomo.Task
+	// actual is the number of completed pomodoros the task was worked on.
+	actual int
+	// misplaced marks a task whose status is not the status of the list.
+	misplaced bool
+	// matches holds the indexes of the runes in the task name matching the
+	// filter.
+	matches []int
 }
 
-func item(t pomo.Task) Item {
+func (m Model) item(t pomo.Task) Item {
 	return Item{
-		Task: t,
+		Task:      t,
+		actual:    m.actuals[t.ID],
+		misplaced: m.status != "" && t.Status != m.status,
 	}
 }
 
@@ -22,14 +36,34 @@ func (i Item) Title() string {
 	return i.Task.Name
 }
 
+// Description returns the checklist progress, if the task has a checklist,
+// followed by the notes.
 func (i Item) Description() string {
-	return i.Task.Notes
+	done, total := i.Task.Progress()
+	switch {
+	case total == 0:
+		return i.Task.Notes
+	case i.Task.Notes == "":
+		return fmt.Sprintf("☑ %d/%d", done, total)
+	default:
+		return fmt.Sprintf("☑ %d/%d · %s", done, total, i.Task.Notes)
+	}
 }
 
 func (i Item) FilterValue() string {
-	return i.Task.Name + i.Task.Notes
+	return filterValue(i.Task)
 }
 
+func filterValue(t pomo.Task) string {
+	value := t.Name + " " + t.Notes + " " + strings.Join(t.Tags, " ")
+	for _, item := range t.Checklist {
+		value += " " + item.Text
+	}
+	return value
+}
+
+// Model is a list of tasks. The list may be filtered to show only the tasks
+// matching a search term, in which case indexes refer to the visible tasks.
 type Model struct {
 	width, height int
 	focused       bool
@@ -37,15 +71,29 @@ type Model struct {
 	focusedBorder lipgloss.Style
 	defaultBorder lipgloss.Style
 
+	// tasks holds all tasks, including those hidden by the filter.
+	tasks   []pomo.Task
+	filter  string
+	visible []int
+
+	// actuals holds the number of completed pomodoros each task was worked
+	// on, by task ID.
+	actuals map[string]int
+	// status is the status of the tasks in the list, if the list is a column
+	// of the board.
+	status pomo.Status
+
 	list list.Model
 }
 
 func New(title string, tasks []pomo.Task) Model {
-	delegate := list.NewDefaultDelegate()
+	delegate := newDelegate(false)
 	delegate.SetSpacing(0)
 
 	l := list.New(nil, delegate, 0, 0)
 	l.Title = title
+	// filtering is applied by SetFilter, so the same filter can be shared by
+	// several lists
 	l.SetFilteringEnabled(false)
 	l.SetShowHelp(false)
 	l.SetShowStatusBar(false)
@@ -84,48 +132,146 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 func (m *Model) Focus(index int) {
 	m.focused = true
 	m.layout()
-	delegate := list.NewDefaultDelegate()
-	m.list.SetDelegate(delegate)
+	m.list.SetDelegate(newDelegate(true))
 	m.Select(index)
 }
 
 func (m *Model) Blur() {
 	m.focused = false
 	m.layout()
-	delegate := list.NewDefaultDelegate()
-	delegate.Styles.SelectedTitle = delegate.Styles.NormalTitle
-	delegate.Styles.SelectedDesc = delegate.Styles.NormalDesc
-	m.list.SetDelegate(delegate)
+	m.list.SetDelegate(newDelegate(false))
 }
 
+// Tasks returns all tasks in the list, including those hidden by the filter.
 func (m Model) Tasks() []pomo.Task {
-	items := m.list.Items()
-	tasks := make([]pomo.Task, len(items))
-	for i, item := range items {
-		tasks[i] = item.(Item).Task
-	}
-	return tasks
+	return slices.Clone(m.tasks)
 }
 
 func (m *Model) SetTasks(tasks []pomo.Task) tea.Cmd {
-	items := make([]list.Item, len(tasks))
-	for index, task := range tasks {
-		items[index] = item(task)
+	m.tasks = slices.Clone(tasks)
+	return m.refresh()
+}
+
+// SetStatus sets the status of the tasks in the list. Tasks with another
+// status are shown with their status.
+func (m *Model) SetStatus(status pomo.Status) tea.Cmd {
+	m.status = status
+	index := m.list.Index()
+	cmd := m.refresh()
+	m.list.Select(index)
+	return cmd
+}
+
+// SetActuals sets the number of completed pomodoros each task was worked on,
+// by task ID, shown against the task estimates.
+func (m *Model) SetActuals(actuals map[string]int) tea.Cmd {
+	m.actuals = actuals
+	index := m.list.Index()
+	cmd := m.refresh()
+	m.list.Select(index)
+	return cmd
+}
+
+// Filter returns the search term tasks are filtered by.
+func (m Model) Filter() string {
+	return m.filter
+}
+
+// SetFilter shows only the tasks that fuzzy match the given term by name,
+// notes or tags. An empty term shows all tasks.
+func (m *Model) SetFilter(term string) tea.Cmd {
+	if term == m.filter {
+		return nil
 	}
+	selected := m.position(m.list.Index())
+	m.filter = term
+	cmd := m.refresh()
+	m.selectPosition(selected)
+	return cmd
+}
 
+// Matches returns whether the task would be shown with the current filter.
+func (m Model) Matches(task pomo.Task) bool {
+	return m.filter == "" || len(list.UnsortedFilter(m.filter, []string{filterValue(task)})) > 0
+}
+
+// refresh updates the visible items from the tasks and the filter.
+func (m *Model) refresh() tea.Cmd {
+	m.visible = nil
+	var items []list.Item
+
+	if m.filter == "" {
+		for i, task := range m.tasks {
+			m.visible = append(m.visible, i)
+			items = append(items, m.item(task))
+		}
+		return m.list.SetItems(items)
+	}
+
+	targets := make([]string, len(m.tasks))
+	for i, task := range m.tasks {
+		targets[i] = filterValue(task)
+	}
+	ranks := list.UnsortedFilter(m.filter, targets)
+	slices.SortFunc(ranks, func(a, b list.Rank) int {
+		return a.Index - b.Index
+	})
+	for _, rank := range ranks {
+		task := m.tasks[rank.Index]
+		nameLength := utf8.RuneCountInString(task.Name)
+
+		it := m.item(task)
+		for _, index := range rank.MatchedIndexes {
+			if index < nameLength {
+				it.matches = append(it.matches, index)
+			}
+		}
+
+		m.visible = append(m.visible, rank.Index)
+		items = append(items, it)
+	}
 	return m.list.SetItems(items)
 }
 
+// position returns the position in the full task list of the task at the
+// given visible index, or -1 if the index is out of range.
+func (m Model) position(index int) int {
+	if index < 0 || index >= len(m.visible) {
+		return -1
+	}
+	return m.visible[index]
+}
+
+// selectPosition selects the visible task at the given position in the full
+// task list, or the nearest visible task before it.
+func (m *Model) selectPosition(pos int) {
+	index := 0
+	for i, p := range m.visible {
+		if p > pos {
+			break
+		}
+		index = i
+	}
+	m.Select(index)
+}
+
 func (m Model) Task(index int) (pomo.Task, bool) {
-	items := m.list.Items()
-	if index < 0 || index >= len(items) {
+	pos := m.position(index)
+	if pos < 0 {
 		return pomo.Task{}, false
 	}
-	return items[index].(Item).Task, true
+	return m.tasks[pos], true
 }
 
 func (m *Model) SetTask(index int, task pomo.Task) tea.Cmd {
-	return m.list.SetItem(index, item(task))
+	pos := m.position(index)
+	if pos < 0 {
+		return nil
+	}
+	m.tasks[pos] = task
+	cmd := m.refresh()
+	m.selectPosition(pos)
+	return cmd
 }
 
 func (m Model) Selection() (pomo.Task, bool) {
@@ -137,8 +283,9 @@ func (m Model) Index() int {
 	return m.list.Index()
 }
 
+// Count returns the number of visible tasks.
 func (m Model) Count() int {
-	return len(m.list.Items())
+	return len(m.visible)
 }
 
 func (m *Model) Select(index int) {
@@ -154,34 +301,57 @@ func (m *Model) Select(index int) {
 
 func (m *Model) Remove() (pomo.Task, bool) {
 	index := m.Index()
-	task, ok := m.Task(index)
-	if ok {
-		m.list.RemoveItem(m.list.Index())
-		m.Select(index)
-		return task, true
+	pos := m.position(index)
+	if pos < 0 {
+		return pomo.Task{}, false
 	}
-	return task, ok
+	task := m.tasks[pos]
+	m.tasks = slices.Delete(m.tasks, pos, pos+1)
+	m.refresh()
+	m.Select(index)
+	return task, true
 }
 
+// Insert inserts the task before the visible task at the given index, or at
+// the end of the list if the index is past the last visible task.
 func (m *Model) Insert(index int, task pomo.Task) tea.Cmd {
-	return m.list.InsertItem(index, item(task))
+	return m.insertAt(m.insertPosition(index), task)
 }
 
 func (m *Model) InsertSelect(index int, task pomo.Task) tea.Cmd {
-	cmd := m.Insert(index, task)
-	m.Select(index)
+	pos := m.insertPosition(index)
+	cmd := m.insertAt(pos, task)
+	m.selectPosition(pos)
 	return cmd
 }
 
 func (m *Model) Append(task pomo.Task) tea.Cmd {
-	items := m.list.Items()
-	items = append(items, item(task))
-	cmd := m.list.SetItems(items)
-	return cmd
+	return m.insertAt(len(m.tasks), task)
 }
 
 func (m *Model) AppendSelect(task pomo.Task) tea.Cmd {
-	return m.InsertSelect(m.Count(), task)
+	pos := len(m.tasks)
+	cmd := m.insertAt(pos, task)
+	m.selectPosition(pos)
+	return cmd
+}
+
+// insertPosition returns the position in the full task list for a task
+// inserted at the given visible index.
+func (m Model) insertPosition(index int) int {
+	switch {
+	case index <= 0:
+		return 0
+	case index >= len(m.visible):
+		return len(m.tasks)
+	default:
+		return m.visible[index]
+	}
+}
+
+func (m *Model) insertAt(pos int, task pomo.Task) tea.Cmd {
+	m.tasks = slices.Insert(m.tasks, pos, task)
+	return m.refresh()
 }
 
 func (m *Model) Up() {
@@ -192,28 +362,32 @@ func (m *Model) Down() {
 	m.list.Select(m.list.Index() + 1)
 }
 
+// MoveUp swaps the selected task with the visible task above it.
 func (m *Model) MoveUp() tea.Cmd {
 	i := m.list.Index()
-	items := m.list.Items()
-	if len(items) > 1 && i > 0 {
-		items[i], items[i-1] = items[i-1], items[i]
-		cmd := m.list.SetItems(items)
-		m.list.Select(i - 1)
-		return cmd
+	if i <= 0 || i >= m.Count() {
+		return nil
 	}
-	return nil
+	return m.swap(i, i-1)
 }
 
+// MoveDown swaps the selected task with the visible task below it.
 func (m *Model) MoveDown() tea.Cmd {
 	i := m.list.Index()
-	items := m.list.Items()
-	if len(items) > 1 && i+1 < len(items) {
-		items[i], items[i+1] = items[i+1], items[i]
-		cmd := m.list.SetItems(items)
-		m.list.Select(i + 1)
-		return cmd
-	}
-	return nil
+	if i < 0 || i+1 >= m.Count() {
+		return nil
+	}
+	return m.swap(i, i+1)
+}
+
+// swap swaps the visible tasks at the given indexes and selects the task at
+// its new index.
+func (m *Model) swap(from, to int) tea.Cmd {
+	a, b := m.visible[from], m.visible[to]
+	m.tasks[a], m.tasks[b] = m.tasks[b], m.tasks[a]
+	cmd := m.refresh()
+	m.list.Select(to)
+	return cmd
 }
 
 func (m *Model) SetSize(w, h int) {
@@ -9,6 +9,11 @@ type StartMsg struct {
 	end time.Time
 }
 
+type PauseMsg struct {
+	id        int
+	remaining time.Duration
+}
+
 type ResetMsg struct {
 	id int
 }
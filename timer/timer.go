@@ -7,6 +7,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/qualidafial/pomo/clock"
 )
 
 var (
@@ -19,6 +20,7 @@ type State int
 const (
 	StateIdle = iota
 	StateActive
+	StatePaused
 	StateTimedOut
 )
 
@@ -31,15 +33,19 @@ func nextID() int {
 
 type Model struct {
 	id    int
+	clock clock.Clock
 	state State
 	// valid when state is active
 	end time.Time
+	// valid when state is paused
+	remaining time.Duration
 }
 
-// New creates a new timer with the given timeout and nextTick interval.
-func New() Model {
+// New creates a new timer that counts down according to the given clock.
+func New(c clock.Clock) Model {
 	return Model{
 		id:    nextID(),
+		clock: c,
 		state: StateIdle,
 	}
 }
@@ -62,6 +68,17 @@ func (m Model) Start(end time.Time) tea.Cmd {
 	}
 }
 
+// Pause stops the countdown with the given amount of time remaining. The
+// timer is resumed by starting it again with a new end time.
+func (m Model) Pause(remaining time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		return PauseMsg{
+			id:        m.id,
+			remaining: remaining,
+		}
+	}
+}
+
 // Reset resets the timer to idle
 func (m Model) Reset() tea.Cmd {
 	return func() tea.Msg {
@@ -71,11 +88,15 @@ func (m Model) Reset() tea.Cmd {
 	}
 }
 
-// Remaining returns the amount of time remaining if the timer is active
+// Remaining returns the amount of time remaining if the timer is active or
+// paused
 func (m Model) Remaining() time.Duration {
 	var remaining time.Duration
+	if m.state == StatePaused {
+		remaining = m.remaining
+	}
 	if m.state == StateActive {
-		remaining = time.Until(m.end)
+		remaining = clock.Until(m.clock, m.end)
 		if remaining < 0 {
 			remaining = 0
 		}
@@ -91,6 +112,10 @@ func (m Model) Active() bool {
 	return m.state == StateActive
 }
 
+func (m Model) Paused() bool {
+	return m.state == StatePaused
+}
+
 func (m Model) TimedOut() bool {
 	return m.state == StateTimedOut
 }
@@ -105,18 +130,27 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 		m.state = StateActive
 		m.end = msg.end
+		m.remaining = 0
 		cmd = m.nextTick()
+	case PauseMsg:
+		if msg.id != m.id {
+			break
+		}
+		m.state = StatePaused
+		m.end = time.Time{}
+		m.remaining = msg.remaining
 	case ResetMsg:
 		if msg.id != m.id {
 			break
 		}
 		m.state = StateIdle
 		m.end = time.Time{}
+		m.remaining = 0
 	case TickMsg:
 		if msg.id != m.id {
 			break
 		}
-		if m.state == StateActive && time.Now().After(m.end) {
+		if m.state == StateActive && m.clock.Now().After(m.end) {
 			m.state = StateTimedOut
 			m.end = time.Time{}
 			cmd = m.timeout()
@@ -131,13 +165,14 @@ func (m Model) nextTick() tea.Cmd {
 		return nil
 	}
 
-	nextTick := time.Until(m.end)%(time.Second/2) + 1
+	nextTick := clock.Until(m.clock, m.end)%(time.Second/2) + 1
 	if nextTick == 0 {
 		nextTick = time.Second / 2
 	}
-	return tea.Tick(nextTick, func(_ time.Time) tea.Msg {
+	return func() tea.Msg {
+		<-m.clock.After(nextTick)
 		return TickMsg{id: m.id}
-	})
+	}
 }
 
 func (m Model) timeout() tea.Cmd {
@@ -164,8 +199,9 @@ func (m Model) View() string {
 	minutes %= 60
 
 	// Show the colon during the upper half of each second (including the exact second).
+	// Keep it steady while paused.
 	colon := ":"
-	if nanos > 0 && nanos <= time.Second/2 {
+	if m.state == StateActive && nanos > 0 && nanos <= time.Second/2 {
 		colon = " "
 	}
 
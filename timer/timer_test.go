@@ -0,0 +1,128 @@
+package timer_test
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/qualidafial/pomo/clock"
+	"github.com/qualidafial/pomo/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+// run executes the command and feeds the resulting messages back into the
+// timer until it settles, advancing the clock whenever a command waits on it.
+// Returns the updated timer and the messages that were not for the timer.
+func run(t *testing.T, c *clock.Fake, m timer.Model, cmd tea.Cmd) (timer.Model, []tea.Msg) {
+	t.Helper()
+
+	var out []tea.Msg
+	queue := []tea.Cmd{cmd}
+	for i := 0; len(queue) > 0; i++ {
+		require.Less(t, i, 1000, "timer did not settle")
+
+		cmd, queue = queue[0], queue[1:]
+		if cmd == nil {
+			continue
+		}
+		var msg tea.Msg
+		c.Run(func() {
+			msg = cmd()
+		})
+		switch msg := msg.(type) {
+		case tea.BatchMsg:
+			queue = append(queue, msg...)
+		case timer.StartMsg, timer.PauseMsg, timer.ResetMsg, timer.TickMsg:
+			m, cmd = m.Update(msg)
+			queue = append(queue, cmd)
+		default:
+			out = append(out, msg)
+		}
+	}
+	return m, out
+}
+
+func TestTimer_Timeout(t *testing.T) {
+	c := clock.NewFake(start)
+	m := timer.New(c)
+	end := start.Add(3 * time.Second)
+
+	m, out := run(t, c, m, m.Start(end))
+
+	assert.True(t, m.TimedOut())
+	assert.Equal(t, []tea.Msg{timer.TimeoutMsg{ID: m.ID()}}, out)
+	assert.True(t, c.Now().After(end))
+	assert.Less(t, c.Now().Sub(end), time.Second)
+	assert.Equal(t, time.Duration(0), m.Remaining())
+}
+
+func TestTimer_EndInPast(t *testing.T) {
+	c := clock.NewFake(start)
+	m := timer.New(c)
+
+	m, out := run(t, c, m, m.Start(start.Add(-time.Minute)))
+
+	assert.True(t, m.TimedOut())
+	assert.Equal(t, []tea.Msg{timer.TimeoutMsg{ID: m.ID()}}, out)
+	assert.Less(t, c.Now().Sub(start), time.Second)
+}
+
+func TestTimer_Tick(t *testing.T) {
+	c := clock.NewFake(start)
+	m := timer.New(c)
+
+	m, _ = m.Update(m.Start(start.Add(90 * time.Second))())
+	assert.True(t, m.Active())
+	assert.Equal(t, "01:30", m.View())
+
+	c.Advance(30*time.Second + time.Second/4)
+	assert.Equal(t, 60*time.Second-time.Second/4, m.Remaining())
+	assert.Equal(t, "01:00", m.View())
+
+	// the colon blinks off during the lower half of each second
+	c.Advance(time.Second / 2)
+	assert.Equal(t, "01 00", m.View())
+}
+
+func TestTimer_Pause(t *testing.T) {
+	c := clock.NewFake(start)
+	m := timer.New(c)
+
+	m, _ = m.Update(m.Start(start.Add(time.Minute))())
+	m, cmd := m.Update(m.Pause(20 * time.Second)())
+	assert.Nil(t, cmd)
+	assert.True(t, m.Paused())
+
+	// time stands still while paused
+	c.Advance(time.Hour)
+	assert.Equal(t, 20*time.Second, m.Remaining())
+	assert.Equal(t, "00:20", m.View())
+
+	m, out := run(t, c, m, m.Start(c.Now().Add(m.Remaining())))
+	assert.True(t, m.TimedOut())
+	assert.Equal(t, []tea.Msg{timer.TimeoutMsg{ID: m.ID()}}, out)
+}
+
+func TestTimer_Reset(t *testing.T) {
+	c := clock.NewFake(start)
+	m := timer.New(c)
+
+	m, _ = m.Update(m.Start(start.Add(time.Minute))())
+	m, cmd := m.Update(m.Reset()())
+	assert.True(t, m.Idle())
+	assert.Nil(t, cmd)
+	assert.Equal(t, "00:00", m.View())
+}
+
+func TestTimer_IgnoresOtherTimers(t *testing.T) {
+	c := clock.NewFake(start)
+	m := timer.New(c)
+	other := timer.New(c)
+
+	m, cmd := m.Update(other.Start(start.Add(time.Minute))())
+	assert.True(t, m.Idle())
+	assert.Nil(t, cmd)
+}
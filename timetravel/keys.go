@@ -0,0 +1,57 @@
+package timetravel
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type KeyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Restore key.Binding
+	Close   key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "newer snapshot"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "older snapshot"),
+		),
+		Restore: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "restore board"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "t"),
+			key.WithHelp("esc", "close time travel"),
+		),
+	}
+}
+
+func (m KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			m.Up,
+			m.Down,
+		},
+		{
+			m.Restore,
+		},
+		{
+			m.Close,
+		},
+	}
+}
+
+func (m KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.Up,
+		m.Down,
+		m.Restore,
+		m.Close,
+	}
+}
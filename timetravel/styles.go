@@ -0,0 +1,58 @@
+package timetravel
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/qualidafial/pomo/color"
+)
+
+type Styles struct {
+	Title    lipgloss.Style
+	Frame    lipgloss.Style
+	Snapshot lipgloss.Style
+	Selected lipgloss.Style
+	Empty    lipgloss.Style
+
+	Heading  lipgloss.Style
+	Added    lipgloss.Style
+	Removed  lipgloss.Style
+	Modified lipgloss.Style
+	Detail   lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Padding(0, 1).
+			Background(lipgloss.Color("62")).
+			Foreground(lipgloss.Color("230")),
+		Frame: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")),
+		Snapshot: lipgloss.NewStyle().
+			Padding(0, 0, 0, 2),
+		Selected: lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("#AD58B4")).
+			Foreground(lipgloss.Color("#EE6FF8")).
+			Padding(0, 0, 0, 1),
+		Empty: lipgloss.NewStyle().
+			Padding(0, 0, 0, 2).
+			Foreground(color.Gray),
+
+		Heading: lipgloss.NewStyle().
+			Bold(true).
+			Padding(0, 0, 0, 1),
+		Added: lipgloss.NewStyle().
+			Padding(0, 0, 0, 1).
+			Foreground(color.Green),
+		Removed: lipgloss.NewStyle().
+			Padding(0, 0, 0, 1).
+			Foreground(color.Red),
+		Modified: lipgloss.NewStyle().
+			Padding(0, 0, 0, 1).
+			Foreground(color.Yellow),
+		Detail: lipgloss.NewStyle().
+			Foreground(color.Gray),
+	}
+}
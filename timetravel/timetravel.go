@@ -0,0 +1,216 @@
+// Package timetravel provides a browser of the snapshots of the current
+// pomodoro, showing how restoring each would change the task board.
+package timetravel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/qualidafial/pomo"
+	"github.com/qualidafial/pomo/message"
+)
+
+const (
+	snapshotListWidth = 26
+	timeFormat        = "Mon Jan 2 15:04:05"
+)
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	width  int
+	height int
+
+	// board is the tasks on the live board, which snapshots are compared to
+	board []pomo.Task
+	times []time.Time
+	index int
+	// snapshots holds the snapshots loaded so far, by Unix time
+	snapshots map[int64]pomo.Pomo
+}
+
+func New() Model {
+	return Model{
+		KeyMap: DefaultKeyMap(),
+		Styles: DefaultStyles(),
+	}
+}
+
+// Open returns a command to load the list of snapshots.
+func (m Model) Open() tea.Cmd {
+	return message.LoadSnapshots
+}
+
+// SetSnapshots sets the times of the snapshots available, newest first, and
+// the tasks on the live board. Returns a command to load the newest snapshot.
+func (m *Model) SetSnapshots(times []time.Time, board []pomo.Task) tea.Cmd {
+	m.times = times
+	m.board = board
+	m.snapshots = make(map[int64]pomo.Pomo)
+	m.index = 0
+	cmd := m.selectSnapshot(0)
+	m.updateKeys()
+	return cmd
+}
+
+// SetSnapshot sets the contents of the snapshot taken at the given time.
+func (m *Model) SetSnapshot(t time.Time, snapshot pomo.Pomo) {
+	m.snapshots[t.Unix()] = snapshot
+	m.updateKeys()
+}
+
+// Snapshot returns the time and contents of the selected snapshot. Returns
+// false if there are no snapshots, or the selected snapshot is not loaded
+// yet.
+func (m Model) Snapshot() (time.Time, pomo.Pomo, bool) {
+	if len(m.times) == 0 {
+		return time.Time{}, pomo.Pomo{}, false
+	}
+	t := m.times[m.index]
+	snapshot, ok := m.snapshots[t.Unix()]
+	return t, snapshot, ok
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.Up):
+			cmd = m.selectSnapshot(m.index - 1)
+		case key.Matches(msg, m.KeyMap.Down):
+			cmd = m.selectSnapshot(m.index + 1)
+		case key.Matches(msg, m.KeyMap.Restore):
+			t, snapshot, ok := m.Snapshot()
+			if ok {
+				cmd = message.RestoreSnapshot(t, snapshot)
+			}
+		case key.Matches(msg, m.KeyMap.Close):
+			cmd = message.CloseTimeTravel
+		}
+	}
+
+	m.updateKeys()
+
+	return m, cmd
+}
+
+// selectSnapshot selects the snapshot at the given index, returning a command
+// to load it if it isn't loaded yet.
+func (m *Model) selectSnapshot(index int) tea.Cmd {
+	m.index = max(0, min(index, len(m.times)-1))
+
+	t, _, ok := m.Snapshot()
+	if ok || t.IsZero() {
+		return nil
+	}
+	return message.LoadSnapshot(t)
+}
+
+func (m *Model) updateKeys() {
+	_, _, loaded := m.Snapshot()
+
+	m.KeyMap.Up.SetEnabled(m.index > 0)
+	m.KeyMap.Down.SetEnabled(m.index+1 < len(m.times))
+	m.KeyMap.Restore.SetEnabled(loaded)
+}
+
+func (m *Model) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+func (m Model) View() string {
+	title := m.Styles.Title.Render("Time travel: " + pomo.Plural(len(m.times), "snapshot"))
+
+	height := max(0, m.height-lipgloss.Height(title))
+	listWidth := min(snapshotListWidth, m.width/2)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		lipgloss.JoinHorizontal(lipgloss.Top,
+			m.viewSnapshots(listWidth, height),
+			m.viewChanges(m.width-listWidth, height),
+		),
+	)
+}
+
+func (m Model) frame(width, height int) lipgloss.Style {
+	return m.Styles.Frame.Copy().
+		Width(max(0, width-m.Styles.Frame.GetHorizontalBorderSize())).
+		Height(max(0, height-m.Styles.Frame.GetVerticalBorderSize()))
+}
+
+func (m Model) viewSnapshots(width, height int) string {
+	frame := m.frame(width, height)
+
+	if len(m.times) == 0 {
+		return frame.Render(m.Styles.Empty.Render("No snapshots"))
+	}
+
+	// scroll to keep the selected snapshot in view
+	visible := max(1, height-m.Styles.Frame.GetVerticalBorderSize())
+	first := max(0, min(m.index-visible/2, len(m.times)-visible))
+	last := min(len(m.times), first+visible)
+
+	var lines []string
+	for i := first; i < last; i++ {
+		line := m.times[i].Local().Format(timeFormat)
+		if i == m.index {
+			line = m.Styles.Selected.Render(line)
+		} else {
+			line = m.Styles.Snapshot.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return frame.Render(strings.Join(lines, "\n"))
+}
+
+func (m Model) viewChanges(width, height int) string {
+	frame := m.frame(width, height)
+
+	t, snapshot, ok := m.Snapshot()
+	switch {
+	case len(m.times) == 0:
+		return frame.Render("")
+	case !ok:
+		return frame.Render(m.Styles.Empty.Render("Loading…"))
+	}
+
+	lines := []string{
+		m.Styles.Heading.Render(fmt.Sprintf("Restoring the board to %s:", t.Local().Format(timeFormat))),
+		"",
+	}
+
+	changes := pomo.DiffTasks(m.board, snapshot.Tasks)
+	if len(changes) == 0 {
+		lines = append(lines, m.Styles.Empty.Render("No changes"))
+	}
+	for _, change := range changes {
+		line := change.Kind.Mark() + " " + change.Task.Name
+		switch change.Kind {
+		case pomo.Added:
+			lines = append(lines, m.Styles.Added.Render(line)+m.Styles.Detail.Render(" in "+change.Task.Status.String()))
+		case pomo.Removed:
+			lines = append(lines, m.Styles.Removed.Render(line))
+		case pomo.Modified:
+			lines = append(lines, m.Styles.Modified.Render(line))
+			for _, field := range change.Fields {
+				lines = append(lines, m.Styles.Detail.Render("    "+field))
+			}
+		}
+	}
+
+	visible := max(1, height-m.Styles.Frame.GetVerticalBorderSize())
+	if len(lines) > visible {
+		more := len(lines) - visible + 1
+		lines = append(lines[:visible-1], m.Styles.Empty.Render(fmt.Sprintf("… %d more", more)))
+	}
+	return frame.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,128 @@
+package pomo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Column is a column of the task board.
+type Column struct {
+	// Status is the key saved with each task in the column, e.g. "doing".
+	Status Status
+	// Name is the title of the column on the board, e.g. "Doing".
+	Name string
+	// Done marks the column of finished tasks, which are archived when a
+	// pomodoro is completed.
+	Done bool
+	// WorkedOn marks the columns of tasks recorded as worked on when a pomodoro
+	// ends.
+	WorkedOn bool
+}
+
+// Workflow is the ordered set of columns a task moves through on the board.
+type Workflow struct {
+	Columns []Column
+}
+
+// DefaultWorkflow returns the To Do, Doing and Done columns.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Columns: []Column{
+			{
+				Status: Todo,
+				Name:   "To Do",
+			},
+			{
+				Status:   Doing,
+				Name:     "Doing",
+				WorkedOn: true,
+			},
+			{
+				Status:   Done,
+				Name:     "Done",
+				Done:     true,
+				WorkedOn: true,
+			},
+		},
+	}
+}
+
+// Validate checks that the workflow has at least one column, that column
+// statuses are unique, and that exactly one column is the done column.
+func (w Workflow) Validate() error {
+	if len(w.Columns) == 0 {
+		return errors.New("no columns")
+	}
+
+	var done int
+	seen := map[Status]bool{}
+	for _, col := range w.Columns {
+		if col.Status == "" {
+			return fmt.Errorf("column %q has no status", col.Name)
+		}
+		if seen[col.Status] {
+			return fmt.Errorf("duplicate status: %s", col.Status)
+		}
+		seen[col.Status] = true
+		if col.Done {
+			done++
+		}
+	}
+	if done != 1 {
+		return fmt.Errorf("expected one done column, got %d", done)
+	}
+	return nil
+}
+
+// Index returns the position of the column with the given status, or -1 if
+// there is no such column.
+func (w Workflow) Index(status Status) int {
+	for i, col := range w.Columns {
+		if col.Status == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// Column returns the column with the given status.
+func (w Workflow) Column(status Status) (Column, bool) {
+	i := w.Index(status)
+	if i < 0 {
+		return Column{}, false
+	}
+	return w.Columns[i], true
+}
+
+// First returns the status of the first column, where new tasks go by
+// default.
+func (w Workflow) First() Status {
+	if len(w.Columns) == 0 {
+		return ""
+	}
+	return w.Columns[0].Status
+}
+
+// Done returns whether tasks with the given status are finished.
+func (w Workflow) Done(status Status) bool {
+	col, ok := w.Column(status)
+	return ok && col.Done
+}
+
+// WorkedOn returns whether tasks with the given status count as worked on.
+func (w Workflow) WorkedOn(status Status) bool {
+	col, ok := w.Column(status)
+	return ok && col.WorkedOn
+}
+
+// ParseStatus returns the status of the column matching the given status or
+// column name, ignoring case.
+func (w Workflow) ParseStatus(s string) (Status, error) {
+	for _, col := range w.Columns {
+		if strings.EqualFold(s, string(col.Status)) || strings.EqualFold(s, col.Name) {
+			return col.Status, nil
+		}
+	}
+	return "", fmt.Errorf("unknown status: %s", s)
+}
@@ -0,0 +1,90 @@
+package pomo_test
+
+import (
+	"testing"
+
+	"github.com/qualidafial/pomo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflow_Validate(t *testing.T) {
+	tests := map[string]struct {
+		columns []pomo.Column
+		wantErr string
+	}{
+		"default": {
+			columns: pomo.DefaultWorkflow().Columns,
+		},
+		"no columns": {
+			wantErr: "no columns",
+		},
+		"missing status": {
+			columns: []pomo.Column{
+				{Name: "Backlog"},
+				{Status: pomo.Done, Done: true},
+			},
+			wantErr: `column "Backlog" has no status`,
+		},
+		"duplicate status": {
+			columns: []pomo.Column{
+				{Status: pomo.Todo, Name: "To Do"},
+				{Status: pomo.Todo, Name: "Next"},
+				{Status: pomo.Done, Done: true},
+			},
+			wantErr: "duplicate status: todo",
+		},
+		"no done column": {
+			columns: []pomo.Column{
+				{Status: pomo.Todo},
+				{Status: pomo.Doing},
+			},
+			wantErr: "expected one done column, got 0",
+		},
+		"two done columns": {
+			columns: []pomo.Column{
+				{Status: pomo.Todo},
+				{Status: "shipped", Done: true},
+				{Status: pomo.Done, Done: true},
+			},
+			wantErr: "expected one done column, got 2",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := pomo.Workflow{Columns: tc.columns}.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkflow_ParseStatus(t *testing.T) {
+	w := pomo.Workflow{
+		Columns: []pomo.Column{
+			{Status: "backlog", Name: "Backlog"},
+			{Status: pomo.Todo, Name: "Next Up"},
+			{Status: pomo.Done, Name: "Done", Done: true},
+		},
+	}
+
+	tests := map[string]pomo.Status{
+		"backlog": "backlog",
+		"BACKLOG": "backlog",
+		"todo":    pomo.Todo,
+		"next up": pomo.Todo,
+		"Next Up": pomo.Todo,
+		"done":    pomo.Done,
+	}
+	for s, want := range tests {
+		got, err := w.ParseStatus(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+
+	_, err := w.ParseStatus("doing")
+	assert.EqualError(t, err, "unknown status: doing")
+}